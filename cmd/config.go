@@ -0,0 +1,385 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type (
+	// setting is a configuration value reachable through `logics config`
+	setting struct {
+		get func() string
+		set func(value string) error
+		// global settings can be overridden by flags and environment
+		// variables, per-project settings only live in the file
+		global bool
+	}
+
+	// repoSetting is a per-project setting, addressed as repos.<name>.<field>
+	repoSetting struct {
+		get func(repo *config.Repo) string
+		set func(conf *config.Conf, repo *config.Repo, value string) error
+	}
+)
+
+var repoSettings = map[string]repoSetting{
+	"only": {
+		get: func(repo *config.Repo) string { return strings.Join(repo.Only, ",") },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			repo.Only = splitList(value)
+			return nil
+		},
+	},
+	"exclude": {
+		get: func(repo *config.Repo) string { return strings.Join(repo.Exclude, ",") },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			repo.Exclude = splitList(value)
+			return nil
+		},
+	},
+	"fetchinclude": {
+		get: func(repo *config.Repo) string { return strings.Join(repo.FetchInclude, ",") },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			repo.FetchInclude = splitList(value)
+			return newClient().SetFetchFilter(repo.Location, logics.FetchFilterOf(*repo))
+		},
+	},
+	"fetchexclude": {
+		get: func(repo *config.Repo) string { return strings.Join(repo.FetchExclude, ",") },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			repo.FetchExclude = splitList(value)
+			return newClient().SetFetchFilter(repo.Location, logics.FetchFilterOf(*repo))
+		},
+	},
+	"ignore": {
+		get: func(repo *config.Repo) string { return strings.Join(repo.Ignore, ",") },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			repo.Ignore = splitList(value)
+			return nil
+		},
+	},
+	"location": {
+		get: func(repo *config.Repo) string { return repo.Location },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			if err := validateDir(value); err != nil {
+				return err
+			}
+			repo.Location = value
+			return nil
+		},
+	},
+	"remote": {
+		get: func(repo *config.Repo) string { return repo.Remote },
+		set: func(conf *config.Conf, repo *config.Repo, value string) error {
+			client := newClient()
+			if !client.IsBare(conf.ResolveRemote(value)) {
+				return fmt.Errorf("%s is not a bare repository", conf.ResolveRemote(value))
+			}
+			// git and the lfs agent must follow, like with relocate-shared
+			if err := client.Relocate(repo.Location, value); err != nil {
+				return fmt.Errorf("could not relocate project %s: %w", repo.Name, err)
+			}
+			repo.Remote = value
+			return nil
+		},
+	},
+}
+
+// lookupSetting returns the setting identified by key within conf
+func lookupSetting(conf *config.Conf, key string) (*setting, error) {
+	switch key {
+	case "sharedfolder":
+		return &setting{
+			get: func() string { return conf.SharedFolder },
+			set: func(value string) error {
+				if err := validateDir(value); err != nil {
+					return err
+				}
+				// the portable remotes follow the shared folder
+				client := newClient()
+				client.SharedFolder = value
+				if err := client.MapSharedRoot(); err != nil {
+					return err
+				}
+				conf.SharedFolder = value
+				return nil
+			},
+			global: true,
+		}, nil
+	case "ignore":
+		return &setting{
+			get: func() string { return strings.Join(conf.Ignore, ",") },
+			set: func(value string) error {
+				conf.Ignore = splitList(value)
+				return nil
+			},
+		}, nil
+	case "projectfolder":
+		return &setting{
+			get: func() string { return conf.ProjectFolder },
+			set: func(value string) error {
+				if err := validateDir(value); err != nil {
+					return err
+				}
+				conf.ProjectFolder = value
+				return nil
+			},
+			global: true,
+		}, nil
+	}
+
+	// repos.<name>.<field>, where the project name may contain dots
+	if strings.HasPrefix(key, "repos.") {
+		i := strings.LastIndex(key, ".")
+		name, field := key[len("repos."):i], key[i+1:]
+		rs, ok := repoSettings[field]
+		if ok && name != "" {
+			idx, err := conf.FindRepo(name)
+			if err != nil {
+				return nil, err
+			}
+			repo := &conf.Repos[idx]
+			return &setting{
+				get: func() string { return rs.get(repo) },
+				set: func(value string) error { return rs.set(conf, repo, value) },
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown setting %s. Run `logics config list` to see the available settings", key)
+}
+
+// settingKeys returns the keys of all settings available within conf
+func settingKeys(conf *config.Conf) []string {
+	keys := []string{"sharedfolder", "projectfolder", "ignore"}
+	fields := make([]string, 0, len(repoSettings))
+	for field := range repoSettings {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, repo := range conf.Repos {
+		for _, field := range fields {
+			keys = append(keys, fmt.Sprintf("repos.%s.%s", repo.Name, field))
+		}
+	}
+	return keys
+}
+
+// splitList parses comma separated values, an empty value meaning none
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// resolve returns the value in effect for a setting and where it comes from
+func resolve(cmd *cobra.Command, key string, s *setting) (string, string) {
+	if s.global {
+		if f := cmd.Flags().Lookup(key); f != nil && f.Changed {
+			return viper.GetString(key), "flag"
+		}
+		if v, ok := override(key); ok {
+			return v, "env"
+		}
+	}
+
+	if v := s.get(); v != "" {
+		return v, "file"
+	}
+	return "", "unset"
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "view and edit the logics settings",
+	Long: `View and edit the logics settings without re-running the setup. Global settings are sharedfolder and projectfolder (which can be overridden with flags or the SHAREDFOLDER and PROJECTFOLDER environment variables), per-project settings are addressed as repos.<project>.<setting>. ignore (globally or per project) overrides the files kept out of the projects, as comma separated .gitignore patterns: a pattern gets ignored on top of the defaults, while !pattern drops a default. For example:
+
+  logics config list
+  logics config get sharedfolder
+  logics config set repos.capelli-curti.location /Volumes/External/capelli-curti
+  logics config set ignore "Bounces/,!*.ovw"
+  logics config edit
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list all settings with their value and origin",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Store.Load()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, key := range settingKeys(cfg) {
+			s, err := lookupSetting(cfg, key)
+			if err != nil {
+				return err
+			}
+			value, source := resolve(cmd, key, s)
+			fmt.Fprintf(w, "%s\t%s\t(%s)\n", key, value, source)
+		}
+		return w.Flush()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "print the value in effect for a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Store.Load()
+		if err != nil {
+			return err
+		}
+
+		s, err := lookupSetting(cfg, args[0])
+		if err != nil {
+			return err
+		}
+
+		value, source := resolve(cmd, args[0], s)
+		if source == "unset" {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "validate and persist a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Store.Load()
+		if err != nil {
+			return err
+		}
+
+		key, value := args[0], strings.TrimSpace(args[1])
+		s, err := lookupSetting(cfg, key)
+		if err != nil {
+			return err
+		}
+
+		if err := s.set(value); err != nil {
+			return err
+		}
+
+		if err := store.Store.Save(cfg); err != nil {
+			return err
+		}
+		Print("preferences saved")
+
+		if _, source := resolve(cmd, key, s); source != "file" {
+			Print("WARNING: the value is currently overridden by a", source)
+		}
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "edit the configuration file with $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !store.Exists() {
+			return errors.New("No config file found for logics. Please run `logics setup` first")
+		}
+
+		original, err := ioutil.ReadFile(store.Path())
+		if err != nil {
+			return err
+		}
+
+		// the file is edited on a copy, so that mistakes never reach the
+		// actual configuration
+		dir, err := ioutil.TempDir("", "logics")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		tmp := filepath.Join(dir, filepath.Base(store.Path()))
+		if err := ioutil.WriteFile(tmp, original, 0644); err != nil {
+			return err
+		}
+
+		if err := editFile(tmp); err != nil {
+			return err
+		}
+
+		edited, err := config.NewStore(tmp).Load()
+		if err != nil {
+			return fmt.Errorf("invalid configuration, nothing saved: %v", err)
+		}
+
+		for _, dir := range []string{edited.SharedFolder, edited.ProjectFolder} {
+			if err := validateDir(dir); err != nil {
+				return fmt.Errorf("invalid configuration, nothing saved: %v", err)
+			}
+		}
+
+		previous, err := store.Store.Load()
+		if err != nil {
+			return err
+		}
+		if err := store.Store.Save(edited); err != nil {
+			return err
+		}
+		Print("preferences saved")
+
+		// the portable remotes follow the shared folder, like with `config set`
+		if edited.SharedFolder != previous.SharedFolder {
+			client := newClient()
+			client.SharedFolder = edited.SharedFolder
+			if err := client.MapSharedRoot(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configEditCmd)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+// consolidateCmd represents the consolidate command
+var consolidateCmd = &cobra.Command{
+	Use:   "consolidate [project]",
+	Short: "copy the audio stored outside of a project into it",
+	Long: `Logic may record or import audio into a global "Audio Files" folder instead of the project, which collaborators then miss. consolidate copies every audio file the songs use from outside the project into the media folder of the song (song.logicx/Media/Audio Files), where Logic finds it by name, so that the next upload shares it. The files to copy are listed before asking for confirmation. For example:
+
+  logics consolidate song --dry-run  # only list the files to copy
+  logics consolidate song            # copy them, after confirmation
+  logics upload song --consolidate   # consolidate, then upload
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		i, err := selectProject(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		plan, err := consolidate(newClient(), cfg.Repos[i], dryRun, yes)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return emit(plan)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(consolidateCmd)
+	consolidateCmd.Flags().Bool("dry-run", false, "only list the files to copy")
+	consolidateCmd.Flags().BoolP("yes", "y", false, "do not ask for confirmation")
+}
+
+// consolidate lists the external audio of repo and, unless dryRun, copies it
+// into the project once confirmed. It returns the plan
+func consolidate(client *logics.Client, repo config.Repo, dryRun, yes bool) ([]logics.Consolidation, error) {
+	plan, err := client.PlanConsolidation(repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan) == 0 {
+		Print(repo.Name + ": no audio outside of the project")
+		return plan, nil
+	}
+
+	var size int64
+	conflicts := 0
+	Print(repo.Name + ": audio outside of the project")
+	for _, p := range plan {
+		if p.Conflict {
+			conflicts++
+			Print(fmt.Sprintf("  %s -> %s (a different file is in the way, skipping)", p.From, p.To))
+			continue
+		}
+		size += p.Size
+		Print(fmt.Sprintf("  %s -> %s, %s", p.From, p.To, progress.HumanBytes(p.Size)))
+	}
+
+	n := len(plan) - conflicts
+	if dryRun || n == 0 {
+		return plan, nil
+	}
+	if !yes {
+		if jsonOutput() {
+			return nil, errors.New("use --yes to consolidate with --output json")
+		}
+		if !common.YNPrompt(fmt.Sprintf("Copy %d file(s) (%s) into %s?", n, progress.HumanBytes(size), repo.Name)) {
+			return nil, errors.New("consolidation aborted")
+		}
+	}
+
+	if _, err := client.Consolidate(repo, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
@@ -16,57 +16,114 @@ limitations under the License.
 package cmd
 
 import (
-	"github.com/autholykos/logics/pkg/common"
-	"github.com/manifoldco/promptui"
+	"fmt"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/logics"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // downloadCmd represents the download command
 var downloadCmd = &cobra.Command{
-	Use:   "download",
+	Use:   "download [project]",
 	Short: "update your local repository with all changes performed remotely",
+	Long: `Download the changes uploaded by the rest of the team. Raw takes are heavy: --lite downloads the bounces only (besides the project files, which are always downloaded), while --include and --exclude pick the audio to download by path, following lfs.fetchinclude and lfs.fetchexclude. The audio left out stays in the project as small placeholders, until ` + "`logics fetch-media`" + ` gets it. The choice is remembered for the project until --full is given. For example:
+
+  logics download song --lite                     # bounces only
+  logics download song --include "Audio Files/Vox*"  # the vocals only
+  logics download song --full                     # everything again
+`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg := &Conf{}
-		if err := viper.Unmarshal(cfg); err != nil {
+		cfg, err := store.Load()
+		if err != nil {
 			return err
 		}
 
-		projects := make([]string, 0)
-		for _, repo := range cfg.Repos {
-			projects = append(projects, repo.Name)
+		i, err := selectProject(cfg, args)
+		if err != nil {
+			return err
 		}
 
-		prompt := promptui.Select{
-			Label: "select which project you want to sync",
-			Items: projects,
+		client := newClient()
+		repo := &cfg.Repos[i]
+		filter, changed := fetchFilterFlags(cmd, logics.FetchFilterOf(*repo))
+		if changed {
+			if err := client.SetFetchFilter(repo.Location, filter); err != nil {
+				return err
+			}
+			repo.FetchInclude, repo.FetchExclude = filter.Include, filter.Exclude
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			Print("download filter remembered for", repo.Name)
+		}
+		if !filter.Empty() {
+			Print(fmt.Sprintf("downloading the audio of %s %s (--full downloads everything)", repo.Name, describeFetchFilter(filter)))
 		}
 
-		i, _, err := prompt.Run()
+		res, err := client.Download(*repo)
 		if err != nil {
 			return err
 		}
 
-		out, err := common.ExecCmd("git", "-C", cfg.Repos[i].Location, "pull", "origin", "master")
-		if err != nil {
-			return err
+		// the audio the new filter picks which the previous one left out
+		if changed {
+			media, err := client.FetchMedia(*repo, nil)
+			if err != nil {
+				return err
+			}
+			res.Bytes += media.Bytes
 		}
 
-		Print(out)
+		if jsonOutput() {
+			return emit(res)
+		}
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(downloadCmd)
+	addFetchFilterFlags(downloadCmd)
+	downloadCmd.Flags().Bool("full", false, "download all the audio, forgetting the filter remembered for the project")
+}
 
-	// Here you will define your flags and configuration settings.
+// addFetchFilterFlags adds the flags picking the audio to download to cmd
+func addFetchFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("lite", false, "download the bounces only, leaving the rest of the audio out (remembered for the project)")
+	cmd.Flags().StringSlice("include", nil, "download only the audio matching these patterns (remembered for the project)")
+	cmd.Flags().StringSlice("exclude", nil, "leave out the audio matching these patterns (remembered for the project)")
+}
+
+// fetchFilterFlags returns the filter picked by the flags of cmd, starting
+// from current, and whether it changed
+func fetchFilterFlags(cmd *cobra.Command, current logics.FetchFilter) (logics.FetchFilter, bool) {
+	filter := current
+	lite, _ := cmd.Flags().GetBool("lite")
+	full, _ := cmd.Flags().GetBool("full")
+	switch {
+	case full:
+		filter = logics.FetchFilter{}
+	case lite:
+		filter = logics.LiteFilter
+	case cmd.Flags().Changed("include") || cmd.Flags().Changed("exclude"):
+		filter.Include, _ = cmd.Flags().GetStringSlice("include")
+		filter.Exclude, _ = cmd.Flags().GetStringSlice("exclude")
+	}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// downloadCmd.PersistentFlags().String("foo", "", "A help for foo")
+	changed := strings.Join(filter.Include, ",") != strings.Join(current.Include, ",") ||
+		strings.Join(filter.Exclude, ",") != strings.Join(current.Exclude, ",")
+	return filter, changed
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// downloadCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+func describeFetchFilter(f logics.FetchFilter) string {
+	parts := make([]string, 0, 2)
+	if len(f.Include) > 0 {
+		parts = append(parts, "matching "+strings.Join(f.Include, ", "))
+	}
+	if len(f.Exclude) > 0 {
+		parts = append(parts, "except "+strings.Join(f.Exclude, ", "))
+	}
+	return strings.Join(parts, " ")
 }
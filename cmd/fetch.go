@@ -0,0 +1,147 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/notify"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// maxNotifiedFiles is how many files a notification names
+const maxNotifiedFiles = 3
+
+// fetchCmd represents the fetch command
+var fetchCmd = &cobra.Command{
+	Use:   "fetch [project...]",
+	Short: "check the shared folder for changes uploaded by your collaborators",
+	Long: `Fetch the projects from the shared folder and report the commits not downloaded yet, with their authors and the files they change. Nothing gets downloaded. With --every, logics keeps running in the background and notifies (on the desktop when possible) only about new changes. For example:
+
+  logics fetch --all                   # check every project once
+  logics fetch --all --every 10m       # keep checking every 10 minutes
+  logics fetch --all --every 10m --notify log # e.g. on a headless machine
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		all, _ := cmd.Flags().GetBool("all")
+		every, _ := cmd.Flags().GetDuration("every")
+		kind, _ := cmd.Flags().GetString("notify")
+
+		repos, err := fetchedRepos(cfg, args, all)
+		if err != nil {
+			return err
+		}
+
+		if kind == "" {
+			kind = "stdout"
+			if every > 0 {
+				kind = "auto"
+			}
+		}
+		notifier, err := notify.New(kind, os.Stdout)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		newClient().Poll(runCtx, repos, every, func(in *logics.Incoming) {
+			found = true
+			if jsonOutput() {
+				_ = emit(in)
+				if kind == "stdout" {
+					return
+				}
+			}
+			if err := notifier.Notify("logics: "+in.Name, describeIncoming(in)); err != nil {
+				log.WithError(err).Warnln("could not notify")
+			}
+		})
+
+		if !found && every == 0 && !jsonOutput() {
+			Print("no new changes")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fetchCmd)
+	fetchCmd.Flags().Bool("all", false, "fetch every project")
+	fetchCmd.Flags().Duration("every", 0, "keep fetching at this interval (e.g. 10m) until interrupted")
+	fetchCmd.Flags().String("notify", "", "how to notify: auto, desktop, stdout or log (default is stdout, auto with --every)")
+}
+
+// fetchedRepos returns the projects named in args, or all of them
+func fetchedRepos(cfg *config.Conf, args []string, all bool) ([]config.Repo, error) {
+	if all {
+		if len(args) > 0 {
+			return nil, errors.New("either name the projects or use --all")
+		}
+		return cfg.Repos, nil
+	}
+	if len(args) == 0 {
+		return nil, errors.New("name the projects to fetch or use --all")
+	}
+
+	repos := make([]config.Repo, 0, len(args))
+	for _, name := range args {
+		i, err := cfg.FindRepo(name)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, cfg.Repos[i])
+	}
+	return repos, nil
+}
+
+// describeIncoming summarizes the incoming commits for a notification
+func describeIncoming(in *logics.Incoming) string {
+	names := make([]string, 0)
+	for _, f := range in.Files() {
+		name := filepath.Base(f)
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	files := strings.Join(names, ", ")
+	if len(names) > maxNotifiedFiles {
+		files = fmt.Sprintf("%s and %d more", strings.Join(names[:maxNotifiedFiles], ", "), len(names)-maxNotifiedFiles)
+	}
+
+	return fmt.Sprintf("%d new commit(s) by %s changing %s. Run `logics download %s` to get them",
+		len(in.Commits), strings.Join(in.Authors(), ", "), files, in.Name)
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
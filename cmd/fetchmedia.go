@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/autholykos/logics/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+// fetchMediaCmd represents the fetch-media command
+var fetchMediaCmd = &cobra.Command{
+	Use:   "fetch-media <pattern>...",
+	Short: "download audio left out by a lite or partial download",
+	Long: `Download the audio matching the patterns, regardless of what ` + "`download --lite`" + `, --include and --exclude left out. Patterns follow lfs.fetchinclude, e.g. "Audio Files/Vox*" or "**/Drums/**". The filter remembered for the project does not change. For example:
+
+  logics fetch-media --project capelli-curti "song.logicx/Media/Audio Files/Vox*"
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		var project []string
+		if name, _ := cmd.Flags().GetString("project"); name != "" {
+			project = []string{name}
+		}
+		i, err := selectProject(cfg, project)
+		if err != nil {
+			return err
+		}
+
+		res, err := newClient().FetchMedia(cfg.Repos[i], args)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return emit(res)
+		}
+		Print(fmt.Sprintf("%s: downloaded %s", res.Name, progress.HumanBytes(res.Bytes)))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fetchMediaCmd)
+	fetchMediaCmd.Flags().String("project", "", "the project to download the audio of (asked when not given)")
+}
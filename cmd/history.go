@@ -0,0 +1,68 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history [project]",
+	Short: "show the latest changes uploaded to a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		i, err := selectProject(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		n, _ := cmd.Flags().GetInt("number")
+		client := newClient()
+		commits, err := client.History(cfg.Repos[i].Location, n)
+		if err != nil {
+			return err
+		}
+		client.Annotate(cfg.Repos[i].Location, commits)
+
+		if jsonOutput() {
+			return emit(commits)
+		}
+
+		for _, c := range commits {
+			Print(fmt.Sprintf("%s  %s  %-20s %s", c.Hash[:7], c.Date, c.Author, c.Subject))
+			for _, m := range c.Music {
+				Print("         " + m)
+			}
+			for _, a := range c.Audio {
+				Print("         " + a.String())
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntP("number", "n", 20, "number of commits to show")
+}
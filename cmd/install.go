@@ -18,42 +18,40 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path"
-	"path/filepath"
 	"strings"
 
-	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v2"
 )
 
 func init() {
 	rootCmd.AddCommand(installCmd)
-	installCmd.PersistentFlags().StringP("projectfolder", "p", "", "specify a target project folder")
+	addFetchFilterFlags(installCmd)
 }
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install",
+	Use:   "install [project]",
 	Short: "install a Logic project hosted on a shared folder",
 	Long: `Install a Logic project in the default Logic folder (or in a the target folder if that is specified). For example:
 
   logics install # install checks for projects within the shared folder and install it on the default Logic directory
+  logics install capelli-curti # install project "capelli-curti" without asking
+  logics install capelli-curti --lite # download the bounces only (see ` + "`logics download --help`" + `)
   logics install -p /path/to/folder # install project "capelli-curti" on /path/to/folder
 `,
+	Args: cobra.MaximumNArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if err := checkSetup(); os.IsNotExist(err) {
+		if !store.Exists() {
 			return errors.New("No config file found for logics. Please run `logics setup` first")
 		}
 
-		folder, _ := cmd.PersistentFlags().GetString("projectfolder")
-		if strings.TrimSpace(folder) == "" {
-			folder = viper.GetString("projectfolder")
-		}
+		// the --projectfolder flag overrides the configured folder
+		folder := strings.TrimSpace(viper.GetString("projectfolder"))
 
 		if _, err := os.Stat(folder); os.IsNotExist(err) {
 			return fmt.Errorf("project folder %s does not exist. Please (re)run `logics setup` or specify a different folder", folder)
@@ -64,40 +62,28 @@ var installCmd = &cobra.Command{
 	},
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		sharedDir := viper.GetString("sharedfolder")
-		cfg := &Conf{}
-		if err := viper.Unmarshal(cfg); err != nil {
-			return err
-		}
-		remoteRepo, err := selectRepo(sharedDir, cfg)
+		cfg, err := store.Load()
 		if err != nil {
 			return err
 		}
-		localDir := viper.GetString("targetdir")
-		basename := strings.TrimSuffix(filepath.Base(remoteRepo), ".git")
-		localRepo := path.Join(localDir, basename)
-		//localRepoGit := fmt.Sprintf("%s.git", localRepo)
-
-		if err := cloneRepo(localRepo, remoteRepo); err != nil {
+		client := newClient()
+		remoteRepo, err := selectRepo(client, cfg, args)
+		if err != nil {
 			return err
 		}
-
-		if err := configureLFSFolderstore(localRepo, remoteRepo); err != nil {
+		filter, _ := fetchFilterFlags(cmd, logics.FetchFilter{})
+		repo, err := client.Install(remoteRepo, viper.GetString("targetdir"), filter)
+		if err != nil {
 			return err
 		}
 
 		Print("new repository installed and configured")
-		cfg.Repos = append(cfg.Repos, Repo{
-			Name:     basename,
-			Location: localRepo,
-		})
-
-		yfg, err := yaml.Marshal(cfg)
-		if err != nil {
+		if err := updateIgnore(cfg, repo); err != nil {
 			return err
 		}
+		cfg.Repos = append(cfg.Repos, repo)
 
-		if err := ioutil.WriteFile(viper.ConfigFileUsed(), yfg, 0644); err != nil {
+		if err := store.Save(cfg); err != nil {
 			return err
 		}
 
@@ -106,70 +92,20 @@ var installCmd = &cobra.Command{
 	},
 }
 
-func cloneRepo(localRepo, remoteRepo string) error {
-	//out, err := common.ExecCmd("git", "-C", localDir, "clone", remoteRepo)
-	out, err := common.ExecCmd("git", "clone", remoteRepo, localRepo)
-	if err != nil {
-		return fmt.Errorf("error in cloning the repo: %v", err)
+// selectRepo returns the bare repository of the project named in args or,
+// when no name is given, lets the user pick one among those not installed yet
+func selectRepo(client *logics.Client, conf *config.Conf, args []string) (string, error) {
+	if len(args) > 0 {
+		return client.FindProject(conf, args[0])
 	}
-	Print(out)
-	return nil
-}
 
-func configureLFSFolderstore(localRepo, remoteRepo string) error {
-	if err := execGit(localRepo, "config", "--add", "lfs.customtransfer.lfs-folder.path", "lfs-folderstore"); err != nil {
-		return err
-	}
-	if err := execGit(localRepo, "config", "--add", "lfs.customtransfer.lfs-folder.args", remoteRepo); err != nil {
-		return err
-	}
-	if err := execGit(localRepo, "config", "--add", "lfs.standalonetransferagent", "lfs-folder"); err != nil {
-		return err
-	}
-	if err := execGit(localRepo, "reset", "--hard", "master"); err != nil {
-		return err
-	}
-	Print("lfs-folderstore configured")
-	return nil
-}
-
-func execGit(localRepo string, args ...string) error {
-	args = append([]string{"-C", localRepo}, args...)
-	out, err := common.ExecCmd("git", args...)
-	if err != nil {
-		return err
-	}
-	Print(out)
-	return nil
-}
-
-func selectRepo(sharedDir string, conf *Conf) (string, error) {
-	projects := make([]string, 0)
-	files, err := ioutil.ReadDir(sharedDir)
+	projects, err := client.Available(conf)
 	if err != nil {
 		return "", err
 	}
 
-	for _, f := range files {
-		if !f.IsDir() {
-			continue
-		}
-
-		// candidate bare repository found
-		repo := path.Join(sharedDir, f.Name())
-		if !isBare(repo) {
-			continue
-		}
-
-		if isAlreadyCloned(repo, conf) {
-			continue
-		}
-		// NOTE: projects are in the form [/path/to/project.git]
-		projects = append(projects, path.Join(sharedDir, f.Name()))
-	}
-
 	if len(projects) == 0 {
-		return "", fmt.Errorf("no new project found in %s", sharedDir)
+		return "", fmt.Errorf("no new project found in %s", client.SharedFolder)
 	}
 
 	prompt := promptui.Select{
@@ -180,53 +116,3 @@ func selectRepo(sharedDir string, conf *Conf) (string, error) {
 	_, repo, err := prompt.Run()
 	return repo, err
 }
-
-func isBare(repo string) bool {
-	out, err := common.ExecCmd("git", "-C", repo, "rev-parse", "--is-bare-repository")
-	if err != nil {
-		return false
-	}
-
-	return strings.TrimSpace(out) == "true"
-}
-
-func isAlreadyCloned(repo string, conf *Conf) bool {
-	if conf.Repos == nil {
-		return false
-	}
-	for _, r := range conf.Repos {
-		if filepath.Base(r.Location) == filepath.Base(repo) {
-			return true
-		}
-	}
-	return false
-}
-
-/* this is for setting up a project
-func track(localRepo string) {
-	audioTypes := []string{"*.wav", "*aif", "*.mp3"}
-	for _, audioType := range audioTypes {
-		out, err := common.ExecCmd("git", "-C", localRepo, "lfs", "track", audioType)
-		if err != nil {
-			return err
-		}
-		fmt.Println(out)
-	}
-		out, err := common.ExecCmd("git", "-C", localRepo, "add", ".gitattributes")
-		if err != nil {
-			return err
-		}
-		fmt.Println(out)
-		out, err := common.ExecCmd("git", "-C", localRepo, "commit", "-m", "add wav, aif and mp3 to the LFS")
-		if err != nil {
-			return err
-		}
-		fmt.Println(out)
-}
-*/
-
-func checkSetup() error {
-	hd, _ := os.UserHomeDir()
-	_, err := os.Stat(path.Join(hd, ".logics.yml"))
-	return err
-}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// lfsAgentCmd is the git-lfs custom transfer agent configured on every
+// project. It resolves the portable remote against the local shared folder
+// and hands over to lfs-folderstore
+var lfsAgentCmd = &cobra.Command{
+	Use:    "lfs-agent <remote>",
+	Short:  "git-lfs transfer agent resolving portable shared folder paths",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		bin, err := exec.LookPath("lfs-folderstore")
+		if err != nil {
+			return fmt.Errorf("lfs-folderstore not found. Please (re)run `logics setup`")
+		}
+
+		// git-lfs talks to the agent through stdin/stdout, which exec hands
+		// over to lfs-folderstore untouched
+		remote := cfg.ResolveRemote(args[0])
+		return syscall.Exec(bin, []string{"lfs-folderstore", remote}, os.Environ())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lfsAgentCmd)
+}
@@ -16,28 +16,96 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/progress"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "list all configured repository by name",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	Short: "list the installed projects and their state",
+	Long: `List the installed projects with their location, branch, last commit, local changes, commits to upload (ahead) and to download (behind) and disk usage. For example:
 
-		cfg := &Conf{}
-		if err := viper.Unmarshal(cfg); err != nil {
+  logics list          # list the installed projects
+  logics list --fetch  # refresh the remote state of every project first
+  logics list --remote # list the projects available in the shared folder
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
 			return err
 		}
 
+		if remote, _ := cmd.Flags().GetBool("remote"); remote {
+			return listRemote(newClient(), cfg)
+		}
+
+		fetch, _ := cmd.Flags().GetBool("fetch")
+		client := newClient()
+		projects := make([]*logics.ProjectInfo, 0, len(cfg.Repos))
 		for _, repo := range cfg.Repos {
-			Print(repo.Name)
+			projects = append(projects, client.Describe(cfg, repo, fetch))
+		}
+
+		if jsonOutput() {
+			return emit(projects)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSONG\tBRANCH\tLAST COMMIT\tCHANGES\tAHEAD/BEHIND\tSIZE\tLOCATION")
+		for _, p := range projects {
+			if !p.Exists {
+				fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\t-\t%s (missing, try `logics relink`)\n", p.Name, p.Location)
+				continue
+			}
+
+			// the first alternative of the first song tells the most
+			song := "-"
+			if len(p.Songs) > 0 && len(p.Songs[0].Alternatives) > 0 {
+				song = p.Songs[0].Alternatives[0].String()
+			}
+
+			last := "-"
+			if p.LastCommit != nil {
+				last = fmt.Sprintf("%s by %s", p.LastCommit.Date, p.LastCommit.Author)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d/%d\t%s\t%s\n", p.Name, song, p.Branch, last, p.Changes, p.Ahead, p.Behind, progress.HumanBytes(p.DiskUsage), p.Location)
 		}
-		return nil
+		return w.Flush()
 	},
 }
 
 func init() {
-	downloadCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().Bool("remote", false, "list the projects available in the shared folder")
+	listCmd.Flags().Bool("fetch", false, "fetch from the shared folder before comparing")
+}
+
+func listRemote(client *logics.Client, cfg *config.Conf) error {
+	remotes, err := client.Remotes(cfg)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		return emit(remotes)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tINSTALLED\tPATH")
+	for _, r := range remotes {
+		installed := "no"
+		if r.Installed {
+			installed = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, installed, r.Path)
+	}
+	return w.Flush()
 }
@@ -0,0 +1,183 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/spf13/cobra"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "bundle the recent logs for a bug report",
+	Long: `Bundle the recent log files (including the one given with --log-file), together with a description of this machine (OS, git and git-lfs versions, configuration), in a tar.gz archive to be attached to a bug report. For example:
+
+  logics logs                 # bundles the logs of the last 7 days in the current folder
+  logics logs --days 1 -f /tmp/logics-bug.tar.gz
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		target, _ := cmd.Flags().GetString("file")
+		if target == "" {
+			target = fmt.Sprintf("logics-logs-%s.tar.gz", time.Now().Format("20060102-150405"))
+		}
+
+		files, err := logFiles()
+		if err != nil {
+			return err
+		}
+
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		gz := gzip.NewWriter(out)
+		tw := tar.NewWriter(gz)
+
+		if err := addToTar(tw, "environment.txt", []byte(environment())); err != nil {
+			return err
+		}
+
+		since := time.Now().AddDate(0, 0, -days)
+		bundled := 0
+		for _, f := range files {
+			fi, err := os.Stat(f.path)
+			if err != nil || !fi.Mode().IsRegular() || fi.ModTime().Before(since) {
+				continue
+			}
+
+			content, err := ioutil.ReadFile(f.path)
+			if err != nil {
+				return err
+			}
+			if err := addToTar(tw, f.name, content); err != nil {
+				return err
+			}
+			bundled++
+		}
+
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+
+		Print(fmt.Sprintf("%d log file(s) bundled in %s", bundled, target))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().Int("days", 7, "bundle the logs written in the last given days")
+	logsCmd.Flags().StringP("file", "f", "", "archive to create (default is logics-logs-<timestamp>.tar.gz)")
+}
+
+// bundledLog is a log file to bundle, with its name within the archive
+type bundledLog struct {
+	path, name string
+}
+
+// logFiles returns the log files to bundle: the ones in the default folder and
+// the one given with --log-file, rotated ones included
+func logFiles() ([]bundledLog, error) {
+	dir, err := common.LogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && logFile == "" {
+		return nil, fmt.Errorf("no logs found in %s: %v", dir, err)
+	}
+
+	seen := make(map[string]bool)
+	files := make([]bundledLog, 0, len(entries))
+	for _, e := range entries {
+		file := filepath.Join(dir, e.Name())
+		seen[file] = true
+		files = append(files, bundledLog{file, path.Join("logs", e.Name())})
+	}
+
+	if logFile == "" {
+		return files, nil
+	}
+	rotated, _ := filepath.Glob(logFile + ".[0-9]*")
+	for _, file := range append([]string{logFile}, rotated...) {
+		abs, err := filepath.Abs(file)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		// apart from the default ones, which may have the same names
+		files = append(files, bundledLog{abs, path.Join("logs", "log-file", filepath.Base(abs))})
+	}
+	return files, nil
+}
+
+func addToTar(tw *tar.Writer, name string, content []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, strings.NewReader(string(content)))
+	return err
+}
+
+// environment describes this machine for bug reports
+func environment() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	for _, c := range [][]string{
+		{"git", "--version"},
+		{"git", "lfs", "version"},
+		{"lfs-folderstore", "--version"},
+	} {
+		out, err := common.ExecCmd(c[0], c[1:]...)
+		if err != nil {
+			out = err.Error()
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", strings.Join(c, " "), strings.TrimSpace(out))
+	}
+
+	fmt.Fprintf(&sb, "\nconfig (%s):\n", store.Path())
+	if content, err := ioutil.ReadFile(store.Path()); err == nil {
+		sb.Write(content)
+	}
+	return sb.String()
+}
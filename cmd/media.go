@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+// mediaCmd groups the commands about the audio of a project
+var mediaCmd = &cobra.Command{
+	Use:   "media",
+	Short: "inspect the audio files of a project",
+}
+
+// mediaCheckCmd represents the `media check` command
+var mediaCheckCmd = &cobra.Command{
+	Use:   "check [project]",
+	Short: "find audio collaborators will miss and audio nobody uses",
+	Long: `Cross-reference the audio used by the songs of a project with the audio in its media folders ("Audio Files", "Media") and what was uploaded. It reports:
+
+  external audio   used by a song but stored outside the project: collaborators get "file not found"
+  missing audio    used by a song but nowhere to be found
+  untracked audio  in the project but never uploaded
+  unused audio     in the project but not used by any song, taking room in the shared folder
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		i, err := selectProject(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		report, err := newClient().CheckMedia(cfg.Repos[i])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return emit(report)
+		}
+
+		if report.Problems() == 0 {
+			Print(report.Name + ": no problems found")
+			return nil
+		}
+
+		printMedia("external audio (collaborators will get \"file not found\", run `logics consolidate`):", report.External)
+		printMedia("missing audio:", report.Missing)
+		printMedia("untracked audio (run `logics upload` to share it):", report.Untracked)
+
+		var unused int64
+		for _, f := range report.Unused {
+			unused += f.Size
+		}
+		printMedia(fmt.Sprintf("unused audio (%s):", progress.HumanBytes(unused)), report.Unused)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mediaCmd)
+	mediaCmd.AddCommand(mediaCheckCmd)
+}
+
+// printMedia prints the files under title, if any
+func printMedia(title string, files []logics.MediaFile) {
+	if len(files) == 0 {
+		return
+	}
+
+	Print(title)
+	for _, f := range files {
+		line := "  " + f.Path
+		if f.Song != "" {
+			line += " (used by " + f.Song + ")"
+		}
+		if f.Size > 0 {
+			line += ", " + progress.HumanBytes(f.Size)
+		}
+		Print(line)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:   "move <project> <new-path>",
+	Short: "move a local project to a different folder",
+	Long: `Move the working copy of a configured project to a new location and update the logics configuration accordingly. For example:
+
+  logics move capelli-curti /Volumes/External/Logic # moves the project to /Volumes/External/Logic/capelli-curti
+  logics move capelli-curti ~/Music/cc               # moves (and renames) the project folder to ~/Music/cc
+`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		i, err := cfg.FindRepo(args[0])
+		if err != nil {
+			return err
+		}
+
+		src := cfg.Repos[i].Location
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			return fmt.Errorf("%s does not exist anymore. Run `logics relink` to find where the project went", src)
+		}
+
+		dst, err := filepath.Abs(args[1])
+		if err != nil {
+			return err
+		}
+
+		// like mv, moving into an existing folder keeps the project name
+		if fi, err := os.Stat(dst); err == nil {
+			if !fi.IsDir() {
+				return fmt.Errorf("%s already exists", dst)
+			}
+			dst = path.Join(dst, filepath.Base(src))
+			if _, err := os.Stat(dst); err == nil {
+				return fmt.Errorf("%s already exists", dst)
+			}
+		}
+
+		if err := moveDir(src, dst); err != nil {
+			return err
+		}
+
+		if _, err := gitCli.Run(dst, "rev-parse", "--git-dir"); err != nil {
+			return fmt.Errorf("project moved to %s, but it does not look like a git repository anymore: %w", dst, err)
+		}
+
+		cfg.Repos[i].Location = dst
+		if err := store.Save(cfg); err != nil {
+			return err
+		}
+		if err := updateIgnore(cfg, cfg.Repos[i]); err != nil {
+			return err
+		}
+
+		Print("project", cfg.Repos[i].Name, "moved to", dst)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+}
+
+// moveDir renames src into dst. When the two folders live on different
+// volumes (e.g. an external drive) the tree gets copied and the source removed
+func moveDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	var lerr *os.LinkError
+	if !errors.As(err, &lerr) || lerr.Err != syscall.EXDEV {
+		return fmt.Errorf("could not move %s to %s: %v", src, dst, err)
+	}
+
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return fmt.Errorf("could not copy %s to %s: %v", src, dst, err)
+	}
+
+	return os.RemoveAll(src)
+}
+
+// copyTree recursively copies directories, regular files and symlinks,
+// preserving permissions
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm())
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(p, target, fi.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+}
+
+// newCmd represents the new command
+var newCmd = &cobra.Command{
+	Use:   "new <folder>",
+	Short: "share a Logic project through the shared folder",
+	Long: `Start tracking a Logic project and share it through the shared folder, so that the rest of the team can install it. For example:
+
+  logics new ~/Music/Logic/capelli-curti # creates capelli-curti.git in the shared folder
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !store.Exists() {
+			return errors.New("No config file found for logics. Please run `logics setup` first")
+		}
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		localRepo, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		if err := validateDir(localRepo); err != nil {
+			return err
+		}
+		if _, err := cfg.FindRepo(filepath.Base(localRepo)); err == nil {
+			return fmt.Errorf("project %s is already configured", filepath.Base(localRepo))
+		}
+
+		// the first upload leaves the junk out already
+		if err := updateIgnore(cfg, config.Repo{Name: filepath.Base(localRepo), Location: localRepo}); err != nil {
+			return err
+		}
+
+		repo, err := newClient().Create(localRepo)
+		if err != nil {
+			return err
+		}
+
+		Print("project shared as", cfg.RemoteOf(repo))
+		cfg.Repos = append(cfg.Repos, repo)
+		return store.Save(cfg)
+	},
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/autholykos/logics/pkg/common"
+)
+
+// outputFormat is either text (default) or json
+var outputFormat string
+
+type (
+	// errorResult is the JSON rendering of a failed command
+	errorResult struct {
+		Error errorDetail `json:"error"`
+	}
+
+	errorDetail struct {
+		Message     string          `json:"message"`
+		Type        *common.ErrType `json:"type,omitempty"`
+		Reason      *common.Reason  `json:"reason,omitempty"`
+		Cmd         string          `json:"cmd,omitempty"`
+		ExitCode    *int            `json:"exitcode,omitempty"`
+		Explanation string          `json:"explanation,omitempty"`
+		Suggestion  string          `json:"suggestion,omitempty"`
+	}
+)
+
+func newErrorResult(err error) *errorResult {
+	res := &errorResult{errorDetail{Message: err.Error()}}
+	var execErr *common.ExecErr
+	if errors.As(err, &execErr) {
+		res.Error.Type = &execErr.Type
+		res.Error.Reason = &execErr.Reason
+		res.Error.Cmd = execErr.Cmd
+		res.Error.ExitCode = &execErr.ExitCode
+		res.Error.Explanation = execErr.Explanation()
+		res.Error.Suggestion = execErr.Suggestion()
+	}
+	return res
+}
+
+// printHint explains a recognized failure and suggests what to do about it
+func printHint(err error) {
+	var execErr *common.ExecErr
+	if !errors.As(err, &execErr) || execErr.Explanation() == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "What happened: %s\nWhat to do: %s\n", execErr.Explanation(), execErr.Suggestion())
+}
+
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// emit writes v as indented JSON on stdout
+func emit(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
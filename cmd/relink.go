@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxRelinkDepth limits how deep relink looks for moved projects
+const maxRelinkDepth = 3
+
+// relinkCmd represents the relink command
+var relinkCmd = &cobra.Command{
+	Use:   "relink [folder...]",
+	Short: "find projects that were moved by hand and repair their location",
+	Long: `Look for configured projects whose location does not exist anymore and try to find them again by searching for a git repository with the same shared remote. By default the project folder is searched, but other folders can be passed as arguments. For example:
+
+  logics relink                      # search the project folder
+  logics relink /Volumes/External    # search an external drive
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		// remote -> index of the project whose location went missing
+		missing := make(map[string]int)
+		for i, repo := range cfg.Repos {
+			if _, err := os.Stat(repo.Location); os.IsNotExist(err) {
+				missing[path.Clean(cfg.RemoteOf(repo))] = i
+			}
+		}
+
+		if len(missing) == 0 {
+			Print("all projects are where they are supposed to be")
+			return nil
+		}
+
+		folders := args
+		if len(folders) == 0 {
+			folders = []string{cfg.ProjectFolder}
+		}
+
+		relinked := 0
+		for _, folder := range folders {
+			for _, candidate := range findRepos(folder, maxRelinkDepth) {
+				out, err := gitCli.Run(candidate, "remote", "get-url", "origin")
+				if err != nil {
+					continue
+				}
+
+				i, ok := missing[path.Clean(strings.TrimSpace(out))]
+				if !ok {
+					continue
+				}
+
+				Print("project", cfg.Repos[i].Name, "found in", candidate)
+				cfg.Repos[i].Location = candidate
+				// not worth losing the projects relinked so far
+				if err := updateIgnore(cfg, cfg.Repos[i]); err != nil {
+					Print("WARNING:", err)
+				}
+				delete(missing, path.Clean(strings.TrimSpace(out)))
+				relinked++
+			}
+		}
+
+		if relinked > 0 {
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			Print("preferences saved")
+		}
+
+		for _, i := range missing {
+			Print("could not find project", cfg.Repos[i].Name, "(last seen in "+cfg.Repos[i].Location+")")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(relinkCmd)
+}
+
+// findRepos returns the working copies found within root, descending at most
+// depth levels. Repositories are not searched for nested repositories
+func findRepos(root string, depth int) []string {
+	if _, err := os.Stat(path.Join(root, ".git")); err == nil {
+		return []string{root}
+	}
+
+	if depth == 0 {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	repos := make([]string, 0)
+	for _, f := range files {
+		// Logic bundles are folders too, but never contain a repository
+		if !f.IsDir() || strings.HasPrefix(f.Name(), ".") || filepath.Ext(f.Name()) == ".logicx" {
+			continue
+		}
+		repos = append(repos, findRepos(path.Join(root, f.Name()), depth-1)...)
+	}
+	return repos
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// relocateSharedCmd represents the `setup relocate-shared` command
+var relocateSharedCmd = &cobra.Command{
+	Use:   "relocate-shared <new-path>",
+	Short: "point logics and all installed projects to a new shared folder",
+	Long: `Use this command when the shared folder moved (e.g. to a different Dropbox folder or to another provider). The shared folder mapping of this machine gets updated and every installed project is (re)configured to use the portable remote. Running it with the current shared folder converts projects installed with absolute remotes. For example:
+
+  logics setup relocate-shared ~/Google\ Drive/logic
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		sharedDir, err := filepath.Abs(strings.TrimSpace(args[0]))
+		if err != nil {
+			return err
+		}
+
+		if err := validateDir(sharedDir); err != nil {
+			return err
+		}
+
+		client := newClient()
+		client.SharedFolder = sharedDir
+
+		// validate everything before touching any repository so that a
+		// half-synced shared folder does not leave projects half-relocated
+		remotes := make([]string, len(cfg.Repos))
+		for i, repo := range cfg.Repos {
+			if _, err := os.Stat(repo.Location); os.IsNotExist(err) {
+				return fmt.Errorf("project %s not found in %s. Run `logics relink` first", repo.Name, repo.Location)
+			}
+
+			remote := path.Join(sharedDir, filepath.Base(cfg.RemoteOf(repo)))
+			if !client.IsBare(remote) {
+				return fmt.Errorf("no repository for project %s found at %s. Is the shared folder fully synced?", repo.Name, remote)
+			}
+			remotes[i] = remote
+		}
+
+		// saving as projects get relocated, so that the configuration keeps
+		// matching them if one fails
+		for i, repo := range cfg.Repos {
+			if err := client.Relocate(repo.Location, config.PortableRemote(remotes[i])); err != nil {
+				return fmt.Errorf("could not relocate project %s: %w", repo.Name, err)
+			}
+			cfg.Repos[i].Remote = config.PortableRemote(remotes[i])
+			if err := store.Save(cfg); err != nil {
+				return err
+			}
+			Print("project", repo.Name, "now points to", remotes[i])
+		}
+
+		if err := client.MapSharedRoot(); err != nil {
+			return err
+		}
+
+		cfg.SharedFolder = sharedDir
+		if err := store.Save(cfg); err != nil {
+			return err
+		}
+
+		Print("preferences saved")
+		return nil
+	},
+}
+
+func init() {
+	setupCmd.AddCommand(relocateSharedCmd)
+}
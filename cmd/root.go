@@ -16,31 +16,47 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/progress"
+	"github.com/manifoldco/promptui"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v2"
 )
 
-type (
-	Repo struct {
-		Name     string `yaml:"name"`
-		Location string `yaml:"location"`
-	}
+var cfgFile string
 
-	Conf struct {
-		SharedFolder  string `yaml:"sharedfolder"`
-		ProjectFolder string `yaml:"projectfolder"`
-		Repos         []Repo `yaml:"repos,flow"`
-	}
+// runCtx is done when the user interrupts logics (or --timeout expires), so
+// that running commands get interrupted gracefully
+var (
+	runCtx  = context.Background()
+	timeout time.Duration
 )
 
-var cfgFile string
+// gitCli runs the git commands, either directly or through the logics client
+var gitCli git.Git
+
+// logging and verbosity flags
+var (
+	verbose, debug, quiet bool
+	logFile               string
+)
+
+// store persists the configuration at cfgFile
+var store *configStore
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -51,9 +67,31 @@ It uses a shared (dropbox) folder as remote repository.
 Internally, files are tracked through git with large file support (git-lfs) and the excellent lfs-folderstore adapter for shared folders.
 For more information visit https://github.com/autholykos/logics
 `,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// silence the annoying help on error
 		cmd.SilenceUsage = true
+
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		setupContext()
+		if gitCli == nil {
+			e := git.NewExec(runCtx)
+			e.OnLine = func(line string) { Print(line) }
+			// progress goes to stderr, leaving stdout to the output
+			e.Progress = func() (progress.Renderer, time.Duration) { return progress.Auto(os.Stderr, quiet) }
+			gitCli = e
+		}
+
+		switch outputFormat {
+		case "text":
+		case "json":
+			// errors get rendered as JSON by Execute
+			cmd.SilenceErrors = true
+		default:
+			return fmt.Errorf("unknown output format %s (use text or json)", outputFormat)
+		}
+		return nil
 	},
 
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,26 +103,121 @@ For more information visit https://github.com/autholykos/logics
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		//fmt.Println(err)
+		if jsonOutput() {
+			_ = emit(newErrorResult(err))
+		} else {
+			printHint(err)
+		}
 		os.Exit(1)
 	}
 }
 
-func WriteYaml(conf *Conf) error {
-	m, err := yaml.Marshal(conf)
-	if err != nil {
-		return err
+// Print writes progress and command output meant for humans. With JSON output
+// it goes to stderr, keeping stdout machine readable
+func Print(out ...interface{}) {
+	if quiet {
+		return
 	}
-	if err := ioutil.WriteFile(cfgFile, m, 0644); err != nil {
-		return err
+	if len(strings.TrimSpace(out[0].(string))) > 0 {
+		if jsonOutput() {
+			fmt.Fprintln(os.Stderr, out...)
+			return
+		}
+		fmt.Println(out...)
+	}
+}
+
+// newClient returns the logics client working with the shared folder of
+// this machine (as configured or overridden)
+func newClient() *logics.Client {
+	c := logics.New(gitCli, viper.GetString("sharedfolder"))
+	c.OnMessage = func(msg string) { Print(msg) }
+	return c
+}
+
+// updateIgnore maintains the managed block of the .gitignore of repo
+func updateIgnore(cfg *config.Conf, repo config.Repo) error {
+	c := newClient()
+	c.Ignore = cfg.Ignore
+	return c.UpdateIgnore(repo)
+}
+
+// setupLogging applies the verbosity flags. Everything, including every
+// executed command, is logged to file regardless of the verbosity
+func setupLogging() error {
+	if quiet && (verbose || debug) {
+		return errors.New("--quiet cannot be combined with --verbose or --debug")
+	}
+
+	opts := common.LogOptions{Level: log.WarnLevel, File: logFile}
+	switch {
+	case debug:
+		opts.Level = log.DebugLevel
+	case verbose:
+		opts.Level = log.InfoLevel
+	case quiet:
+		opts.Level = log.ErrorLevel
+	}
+
+	if opts.File != "" {
+		return common.SetupLogging(opts)
+	}
+
+	// failing to write the default log file should not prevent logics from
+	// working (e.g. on a read-only home)
+	opts.File, _ = common.DefaultLogFile()
+	if err := common.SetupLogging(opts); err != nil {
+		log.WithError(err).Warnln("logging to file disabled")
+		opts.File = ""
+		return common.SetupLogging(opts)
 	}
 	return nil
 }
 
-func Print(out ...interface{}) {
-	if len(strings.TrimSpace(out[0].(string))) > 0 {
-		fmt.Println(out...)
+// setupContext cancels runCtx on the first SIGINT/SIGTERM, leaving the
+// running command the time to clean up. A second signal exits right away
+func setupContext() {
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		runCtx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		runCtx, cancel = context.WithCancel(context.Background())
 	}
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		log.Warnln("interrupting... (press Ctrl-C again to quit immediately)")
+		cancel()
+		<-sigs
+		os.Exit(130)
+	}()
+}
+
+// selectProject returns the index of the project named in args or, when no
+// name is given, lets the user pick one
+func selectProject(cfg *config.Conf, args []string) (int, error) {
+	if len(args) > 0 {
+		return cfg.FindRepo(args[0])
+	}
+
+	if len(cfg.Repos) == 0 {
+		return -1, errors.New("no project installed yet. Run `logics install` first")
+	}
+
+	projects := make([]string, 0)
+	for _, repo := range cfg.Repos {
+		projects = append(projects, repo.Name)
+	}
+
+	prompt := promptui.Select{
+		Label: "select which project you want to sync",
+		Items: projects,
+	}
+
+	i, _, err := prompt.Run()
+	return i, err
 }
 
 func init() {
@@ -94,29 +227,43 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.logics.yml)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "print the commands executed by logics")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "print debugging information")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print errors")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "log file (default is $HOME/.logics/logs/logics.log)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "interrupt git operations taking longer than this (e.g. 30m). Zero means no timeout")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "output format: text or json")
+	rootCmd.PersistentFlags().String("sharedfolder", "", "override the configured shared folder")
+	rootCmd.PersistentFlags().StringP("projectfolder", "p", "", "override the configured project folder")
+	_ = viper.BindPFlag("sharedfolder", rootCmd.PersistentFlags().Lookup("sharedfolder"))
+	_ = viper.BindPFlag("projectfolder", rootCmd.PersistentFlags().Lookup("projectfolder"))
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
+	if cfgFile == "" {
 		// Find home directory.
 		home, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-
-		// Search config in home directory with name ".logics" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".logics")
 		cfgFile = path.Join(home, ".logics.yml")
 	}
 
+	// viper reads the very same file the store writes, so that flags and
+	// environment variables can override it
+	store = &configStore{config.NewStore(cfgFile)}
+	viper.SetConfigFile(cfgFile)
+
 	viper.AutomaticEnv() // read in environment variables that match
 
+	// loading migrates older config files before viper reads them
+	if _, err := store.Load(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	// If a config file is found, read it in.
 	_ = viper.ReadInConfig()
 }
@@ -0,0 +1,212 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// kinds are the kinds of files in the order they are listed
+var kinds = []string{"project", "recording", "bounce", "other"}
+
+// updateSelection applies the --only, --exclude, --all and --select flags of
+// upload to the selection remembered for cfg.Repos[i], saving it if changed
+func updateSelection(cmd *cobra.Command, client *logics.Client, cfg *config.Conf, i int) error {
+	repo := &cfg.Repos[i]
+	previous := logics.SelectionOf(*repo)
+	sel := previous
+
+	only, _ := cmd.Flags().GetStringSlice("only")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	all, _ := cmd.Flags().GetBool("all")
+	interactive, _ := cmd.Flags().GetBool("select")
+	switch {
+	case all:
+		sel = logics.Selection{}
+	case interactive:
+		changes, err := client.Git.Status(repo.Location)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return logics.ErrNoChanges
+		}
+		excluded, err := pickChanges(logics.Expand(repo.Location, changes), previous)
+		if err != nil {
+			return err
+		}
+		sel = logics.Selection{Exclude: excluded}
+	case cmd.Flags().Changed("only") || cmd.Flags().Changed("exclude"):
+		sel = logics.Selection{Only: only, Exclude: exclude}
+	}
+
+	if !sel.Empty() {
+		Print(fmt.Sprintf("uploading the changes selected for %s: %s (--all uploads everything)", repo.Name, describeSelection(sel)))
+	}
+	if equalSelections(sel, previous) {
+		return nil
+	}
+
+	repo.Only, repo.Exclude = sel.Only, sel.Exclude
+	if err := store.Save(cfg); err != nil {
+		return err
+	}
+	Print("selection remembered for", repo.Name)
+	return nil
+}
+
+func describeSelection(sel logics.Selection) string {
+	parts := make([]string, 0, 2)
+	if len(sel.Only) > 0 {
+		parts = append(parts, "only "+strings.Join(sel.Only, ", "))
+	}
+	if len(sel.Exclude) > 0 {
+		parts = append(parts, "excluding "+strings.Join(sel.Exclude, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func equalSelections(a, b logics.Selection) bool {
+	return strings.Join(a.Only, "\x00") == strings.Join(b.Only, "\x00") &&
+		strings.Join(a.Exclude, "\x00") == strings.Join(b.Exclude, "\x00")
+}
+
+// pickChanges lets the user toggle the changes to upload, grouped by kind,
+// starting from the ones picked by sel. It returns the files left out
+func pickChanges(changes []git.Change, sel logics.Selection) ([]string, error) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return kindOrder(logics.Kind(changes[i].Path)) < kindOrder(logics.Kind(changes[j].Path))
+	})
+	picked := make([]bool, len(changes))
+	for i, ch := range changes {
+		picked[i] = sel.Includes(ch.Path)
+	}
+
+	// every kind gets a line toggling all of its files, followed by them
+	type entry struct {
+		kind   string
+		change int // -1 for the line of the kind
+	}
+	entries := []entry{{change: -1}}
+	for _, k := range kinds {
+		first := true
+		for i, ch := range changes {
+			if logics.Kind(ch.Path) != k {
+				continue
+			}
+			if first {
+				entries = append(entries, entry{kind: k, change: -1})
+				first = false
+			}
+			entries = append(entries, entry{kind: k, change: i})
+		}
+	}
+
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	cursor := 0
+	for {
+		items := make([]string, len(entries))
+		n := 0
+		for _, p := range picked {
+			if p {
+				n++
+			}
+		}
+		for i, e := range entries {
+			switch {
+			case e.kind == "":
+				items[i] = fmt.Sprintf("done: upload %d of %d change(s)", n, len(changes))
+			case e.change < 0:
+				items[i] = fmt.Sprintf("%s all %ss", check(allPicked(changes, picked, e.kind)), e.kind)
+			default:
+				ch := changes[e.change]
+				items[i] = fmt.Sprintf("    %s %s %s", check(picked[e.change]), ch.Status, ch.Path)
+			}
+		}
+
+		const size = 15
+		scroll := 0
+		if cursor >= size {
+			scroll = cursor - size + 1
+		}
+		prompt := promptui.Select{
+			Label: "Select the changes to upload",
+			Items: items,
+			Size:  size,
+		}
+		idx, _, err := prompt.RunCursorAt(cursor, scroll)
+		if err != nil {
+			return nil, err
+		}
+		cursor = idx
+
+		e := entries[idx]
+		switch {
+		case e.kind == "":
+			excluded := make([]string, 0)
+			for i, ch := range changes {
+				if !picked[i] {
+					excluded = append(excluded, ch.Path)
+				}
+			}
+			if len(excluded) == len(changes) {
+				return nil, logics.ErrNothingSelected
+			}
+			return excluded, nil
+		case e.change < 0:
+			on := !allPicked(changes, picked, e.kind)
+			for i, ch := range changes {
+				if logics.Kind(ch.Path) == e.kind {
+					picked[i] = on
+				}
+			}
+		default:
+			picked[e.change] = !picked[e.change]
+		}
+	}
+}
+
+// allPicked tells whether every change of kind is picked
+func allPicked(changes []git.Change, picked []bool, kind string) bool {
+	for i, ch := range changes {
+		if logics.Kind(ch.Path) == kind && !picked[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func kindOrder(kind string) int {
+	for i, k := range kinds {
+		if k == kind {
+			return i
+		}
+	}
+	return len(kinds)
+}
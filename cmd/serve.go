@@ -0,0 +1,136 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// serveInfo tells front-ends where the API is and which token to use
+type serveInfo struct {
+	Address string `json:"address"`
+	Token   string `json:"token"`
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve the logics operations through a local HTTP/JSON API",
+	Long: `Serve list, status, history, download, upload and project locking through a HTTP/JSON API on localhost, streaming messages and progress as server-sent events, so that graphical front-ends can drive logics. Every request must carry the token (as "Authorization: Bearer <token>" or ?token=<token>). While running, the address and the token are written to $HOME/.logics/serve.json, readable only by the user. A lock (a file in the shared repository of the project) tells the rest of the team who is working on a project: it is advisory, nothing stops an upload. For example:
+
+  logics serve                        # listen on 127.0.0.1:7337 with a random token
+  logics serve --addr 127.0.0.1:9000 --token secret
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !store.Exists() {
+			return errors.New("No config file found for logics. Please run `logics setup` first")
+		}
+
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			var err error
+			if token, err = server.NewToken(); err != nil {
+				return err
+			}
+		}
+
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			log.Warnf("%s is reachable from other machines: anybody knowing the token can change your projects", addr)
+		}
+
+		// the server gets its own git client, reporting to the front-ends
+		// rather than to the terminal
+		e := git.NewExec(runCtx)
+		client := logics.New(e, viper.GetString("sharedfolder"))
+		if cfg, err := store.Load(); err == nil {
+			client.Ignore = cfg.Ignore
+		}
+		srv := server.New(store, client, token)
+		e.OnLine, e.Progress, client.OnMessage = srv.Message, srv.Progress, srv.Message
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+
+		info, err := writeServeInfo(serveInfo{Address: "http://" + l.Addr().String(), Token: token})
+		if err != nil {
+			return err
+		}
+		defer os.Remove(info)
+
+		httpSrv := &http.Server{Handler: srv}
+		go func() {
+			<-runCtx.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = httpSrv.Shutdown(ctx)
+		}()
+
+		Print("serving on http://"+l.Addr().String(), "(address and token in "+info+")")
+		if err := httpSrv.Serve(l); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("addr", "127.0.0.1:7337", "address to listen on")
+	serveCmd.Flags().String("token", "", "token the front-ends must present (default is a random one)")
+}
+
+// writeServeInfo writes where the API is for front-ends to find it,
+// returning the file written
+func writeServeInfo(info serveInfo) (string, error) {
+	hd, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := path.Join(hd, ".logics")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	file := path.Join(dir, "serve.json")
+	return file, ioutil.WriteFile(file, b, 0600)
+}
@@ -25,11 +25,14 @@ import (
 
 	"github.com/autholykos/logics/pkg/common"
 	"github.com/manifoldco/promptui"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
-var dropboxFolder, logicFolder, tmpDir string
+var dropboxFolder, logicFolder string
+
+// assumeYes answers yes to every question, making setup non interactive
+var assumeYes bool
 
 func init() {
 	hd, err := os.UserHomeDir()
@@ -38,8 +41,8 @@ func init() {
 	}
 	dropboxFolder = path.Join(hd, "Dropbox", "logic")
 	logicFolder = path.Join(hd, "Music", "Logic")
-	tmpDir, _ = ioutil.TempDir("", "logics")
 
+	setupCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "do not ask for confirmation (folders not given through --sharedfolder and --projectfolder take the default)")
 	rootCmd.AddCommand(setupCmd)
 }
 
@@ -47,52 +50,76 @@ func init() {
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "setup logics configuration",
-	Long:  `Setup the yaml file used to persist configuration attributes for using logics`,
+	Long: `Setup the yaml file used to persist configuration attributes for using logics. For example:
+
+  logics setup # asks for the shared and project folders
+  logics setup --sharedfolder ~/Dropbox/logic -p ~/Music/Logic --yes # no questions asked
+`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		_, err := common.ExecCmd("git", "--version")
+		_, err := gitCli.Run("", "--version")
 		if err != nil {
 			return errors.New("no git installation found")
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return Setup()
-	},
-	PostRunE: func(cmd *cobra.Command, args []string) error {
-		if err := os.RemoveAll(tmpDir); err != nil {
-			return fmt.Errorf("WARNING: could not remove %s", tmpDir)
-		}
-		return nil
+		sharedDir, _ := cmd.Flags().GetString("sharedfolder")
+		projDir, _ := cmd.Flags().GetString("projectfolder")
+		return Setup(strings.TrimSpace(sharedDir), strings.TrimSpace(projDir))
 	},
 }
 
-func Setup() error {
-	cfg := strings.TrimSpace(viper.ConfigFileUsed())
-	if len(cfg) > 0 {
+// Setup configures logics and installs its dependencies. The folders are
+// asked for unless given
+func Setup(sharedDir, projDir string) error {
+	cfg := store.Path()
+	if store.Exists() && !assumeYes {
 		if !common.YNPrompt(fmt.Sprintf("A setup was likely already run (and created the configuration at %s). Do you want to re-run the setup?", cfg)) {
 			Print("Okidokey")
 			return nil
 		}
 	}
 
-	conf := &Conf{}
-	sharedDir, err := setupSharedDir()
+	// re-running the setup keeps the installed projects
+	// the folders given are saved, rather than overriding the file
+	conf, err := store.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	sharedDir, err = setupSharedDir(sharedDir)
 	if err != nil {
 		return err
 	}
 	conf.SharedFolder = sharedDir
 
-	projDir, err := setupProjectDir()
+	client := newClient()
+	client.SharedFolder = sharedDir
+	if err := client.MapSharedRoot(); err != nil {
+		return err
+	}
+
+	projDir, err = setupProjectDir(projDir)
 	if err != nil {
 		return err
 	}
 	conf.ProjectFolder = projDir
 
-	if err := WriteYaml(conf); err != nil {
+	if err := store.Store.Save(conf); err != nil {
 		return fmt.Errorf("Something went wrong with writing config file %s, %v", cfg, err)
 	}
 	Print("Preferences saved", cfg)
 
+	tmpDir, err := ioutil.TempDir("", "logics")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.WithError(err).Warnf("could not remove %s", tmpDir)
+		}
+	}()
+
 	if err := common.InstallGitLFS(tmpDir); err != nil {
 		return err
 	}
@@ -106,7 +133,14 @@ func Setup() error {
 }
 
 // setupSharedDir sets up the shared repository
-func setupSharedDir() (string, error) {
+func setupSharedDir(result string) (string, error) {
+	if result == "" && assumeYes {
+		result = dropboxFolder
+	}
+	if result != "" {
+		return result, validateDir(result)
+	}
+
 	prompt := promptui.Prompt{
 		Label:   "Please input the shared folder path",
 		Default: dropboxFolder,
@@ -130,20 +164,25 @@ func setupSharedDir() (string, error) {
 }
 
 // setupProjectDir sets up the folder with the Logic projects
-func setupProjectDir() (string, error) {
-	prompt := promptui.Prompt{
-		Label:   "Please input your project folder",
-		Default: logicFolder,
+func setupProjectDir(result string) (string, error) {
+	if result == "" && assumeYes {
+		result = logicFolder
 	}
+	if result == "" {
+		prompt := promptui.Prompt{
+			Label:   "Please input your project folder",
+			Default: logicFolder,
+		}
 
-	result, err := prompt.Run()
-	if err != nil {
-		return "", err
+		var err error
+		if result, err = prompt.Run(); err != nil {
+			return "", err
+		}
+		result = strings.TrimSpace(result)
 	}
-	result = strings.TrimSpace(result)
 
 	if err := validateDir(result); err != nil {
-		if !common.YNPrompt(fmt.Sprintf("Cannot find %s. Do you want to create it?", result)) {
+		if !assumeYes && !common.YNPrompt(fmt.Sprintf("Cannot find %s. Do you want to create it?", result)) {
 			return "", errors.New("Cannot setup without a project folder")
 		}
 
@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [project]",
+	Short: "show the local changes of a project and how it compares to the remote",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		i, err := selectProject(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+		res, err := client.Status(cfg.Repos[i])
+		if err != nil {
+			return err
+		}
+
+		untrack, _ := cmd.Flags().GetBool("untrack")
+		if jsonOutput() {
+			if untrack {
+				if err := client.Untrack(res.Location, res.Junk); err != nil {
+					return err
+				}
+			}
+			return emit(res)
+		}
+
+		Print(fmt.Sprintf("%s (%s) on branch %s", res.Name, res.Location, res.Branch))
+		Print(fmt.Sprintf("%d commit(s) to upload, %d commit(s) to download", res.Ahead, res.Behind))
+		if err := client.CheckLock(cfg.Repos[i], ""); errors.Is(err, logics.ErrLocked) {
+			Print("WARNING: " + err.Error() + ", hold off your changes")
+		} else if res.Lock != nil {
+			Print("locked by " + res.Lock.String())
+		}
+		if f := logics.FetchFilterOf(cfg.Repos[i]); !f.Empty() {
+			Print(fmt.Sprintf("downloading the audio %s only (see `logics download --full`)", describeFetchFilter(f)))
+		}
+		for _, s := range res.Songs {
+			for _, alt := range s.Alternatives {
+				Print(fmt.Sprintf("%s, %s: %s", s.Name(), alt.Label(), alt))
+			}
+		}
+		if len(res.Changes) == 0 {
+			Print("no local changes")
+		} else {
+			Print("local changes:")
+		}
+		for _, c := range res.Changes {
+			Print(c.Status, c.Path)
+		}
+		if len(res.Music) > 0 {
+			Print("musical changes:")
+			for _, m := range res.Music {
+				Print("  " + m)
+			}
+		}
+		if len(res.Audio) > 0 {
+			Print("audio:")
+			for _, a := range res.Audio {
+				Print("  " + a.String())
+			}
+		}
+
+		if len(res.Junk) > 0 {
+			return warnJunk(client, res, untrack)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().Bool("untrack", false, "stop tracking the uploaded files which should be kept out of the project, without asking")
+}
+
+// warnJunk lists the files uploaded although they should be kept out of the
+// project and offers to stop tracking them
+func warnJunk(client *logics.Client, res *logics.ProjectStatus, untrack bool) error {
+	Print(fmt.Sprintf("WARNING: %d file(s) which should be kept out of the project are uploaded:", len(res.Junk)))
+	for _, f := range res.Junk {
+		Print("  " + f)
+	}
+
+	if !untrack {
+		if !isatty.IsTerminal(os.Stdin.Fd()) {
+			Print("run `logics status --untrack` to stop tracking them")
+			return nil
+		}
+		untrack = common.YNPrompt("Stop tracking them (they stay on disk)?")
+	}
+	if !untrack {
+		return nil
+	}
+
+	if err := client.Untrack(res.Location, res.Junk); err != nil {
+		return err
+	}
+	Print(fmt.Sprintf("%d file(s) not tracked anymore, run `logics upload` to remove them from the shared project", len(res.Junk)))
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/spf13/viper"
+)
+
+// configStore applies the overrides of the global flags and the environment
+// variables to the configuration it loads, so that every command works with
+// the same folders. The overrides never get saved: setup and config, which
+// change the file itself, go through the embedded Store
+type configStore struct {
+	*config.Store
+}
+
+// Load reads the configuration file and applies the overrides
+func (s *configStore) Load() (*config.Conf, error) {
+	conf, err := s.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := override("sharedfolder"); ok {
+		conf.SharedFolder = v
+	}
+	if v, ok := override("projectfolder"); ok {
+		conf.ProjectFolder = v
+	}
+	return conf, nil
+}
+
+// Save writes conf, keeping the values of the file where overridden (unless
+// the command changed them, e.g. relocate-shared)
+func (s *configStore) Save(conf *config.Conf) error {
+	file, err := s.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	saved := *conf
+	if v, ok := override("sharedfolder"); ok && saved.SharedFolder == v {
+		saved.SharedFolder = file.SharedFolder
+	}
+	if v, ok := override("projectfolder"); ok && saved.ProjectFolder == v {
+		saved.ProjectFolder = file.ProjectFolder
+	}
+	return s.Store.Save(&saved)
+}
+
+// override returns the value a global setting is overridden with, by its
+// flag or its environment variable, if any
+func override(key string) (string, bool) {
+	if f := rootCmd.PersistentFlags().Lookup(key); f != nil && f.Changed {
+		return viper.GetString(key), true
+	}
+	if _, ok := os.LookupEnv(strings.ToUpper(key)); ok {
+		return viper.GetString(key), true
+	}
+	return "", false
+}
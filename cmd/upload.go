@@ -17,49 +17,72 @@ package cmd
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
 	"strings"
 
-	"github.com/autholykos/logics/pkg/common"
-	"github.com/manifoldco/promptui"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // uploadCmd represents the upload command
 var uploadCmd = &cobra.Command{
-	Use:   "upload",
+	Use:   "upload [project]",
 	Short: "upload your modification to the remote repository",
+	Long: `Commit the local changes of a project (all of them, unless selected) and upload them to the shared folder. Patterns given to --only and --exclude match the path or the name of a file, a folder matches everything within it and @project, @recordings, @bounces and @other match the files of that kind. The selection is remembered for the project until --all is given. Unless a message is given, the commit message describes the changes, e.g. "Added 3 recordings (Vox_Take4.wav, Vox_Take5.wav…), replaced bounce Bass.wav, changed tempo 92 → 94 BPM". For example:
+
+  logics upload song                                    # upload with the generated message
+  logics upload song -m "new vocals"                    # upload with your own message
+  logics upload song --edit                             # review the generated message with $EDITOR first
+  logics upload song --consolidate                      # copy the audio stored outside of the project into it first
+  logics upload song --exclude "Scratch/,*_scratch.wav" # leave out the scratch recordings
+  logics upload song --only @project,@bounces           # share the project and the bounces only
+  logics upload song --select                           # pick the changes from a list
+  logics upload song --all                              # upload everything again
+`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg := &Conf{}
-		if err := viper.Unmarshal(cfg); err != nil {
+		cfg, err := store.Load()
+		if err != nil {
 			return err
 		}
 
-		projects := make([]string, 0)
-		for _, repo := range cfg.Repos {
-			projects = append(projects, repo.Name)
-		}
-
-		prompt := promptui.Select{
-			Label: "select which project you want to sync",
-			Items: projects,
-		}
-
-		i, _, err := prompt.Run()
+		i, err := selectProject(cfg, args)
 		if err != nil {
 			return err
 		}
 
-		repo := cfg.Repos[i].Location
-		if err := checkChanges(repo); err != nil {
+		// before picking the changes, so that junk is not even listed
+		client := newClient()
+		client.Ignore = cfg.Ignore
+		if err := client.UpdateIgnore(cfg.Repos[i]); err != nil {
+			return err
+		}
+		if c, _ := cmd.Flags().GetBool("consolidate"); c {
+			if _, err := consolidate(client, cfg.Repos[i], false, false); err != nil {
+				return err
+			}
+		}
+		if err := updateSelection(cmd, client, cfg, i); err != nil {
 			return err
 		}
 
 		msg, _ := cmd.PersistentFlags().GetString("message")
-		if err := push(repo, msg); err != nil {
+		if edit, _ := cmd.Flags().GetBool("edit"); edit {
+			if msg, err = editMessage(client, cfg.Repos[i], msg); err != nil {
+				return err
+			}
+		}
+
+		res, err := client.Upload(cfg.Repos[i], msg)
+		if err != nil {
 			return err
 		}
 
+		if jsonOutput() {
+			return emit(res)
+		}
 		return nil
 	},
 }
@@ -71,38 +94,67 @@ func init() {
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
-	uploadCmd.PersistentFlags().StringP("message", "m", "committing work on Logic", "specify a message for your commit")
+	uploadCmd.PersistentFlags().StringP("message", "m", "", "specify a message for your commit (generated from the changes by default)")
+	uploadCmd.Flags().BoolP("edit", "e", false, "edit the commit message with $EDITOR before uploading")
+	uploadCmd.Flags().StringSlice("only", nil, "upload only the changes matching these patterns (remembered for the project)")
+	uploadCmd.Flags().StringSlice("exclude", nil, "leave out the changes matching these patterns (remembered for the project)")
+	uploadCmd.Flags().Bool("select", false, "pick the changes to upload from a list (remembered for the project)")
+	uploadCmd.Flags().Bool("all", false, "upload every change, forgetting the selection remembered for the project")
+	uploadCmd.Flags().Bool("consolidate", false, "copy the audio stored outside of the project into it first (see `logics consolidate`)")
 }
 
-func checkChanges(repo string) error {
-
-	out, err := common.ExecCmd("git", "-C", repo, "status", "--porcelain")
+// editMessage lets the user edit the commit message for the selected changes
+// of repo, starting from msg or from the generated one. Lines starting with #
+// are dropped
+func editMessage(client *logics.Client, repo config.Repo, msg string) (string, error) {
+	changes, err := client.Selected(repo)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", logics.ErrNoChanges
+	}
+	if msg == "" {
+		msg = client.CommitMessage(repo.Location, changes)
 	}
 
-	if len(strings.TrimSpace(out)) == 0 {
-		return errors.New("no changes detected: nothing to do!")
+	var sb strings.Builder
+	sb.WriteString(msg + "\n\n")
+	sb.WriteString("# Describe your changes. Lines starting with '#' are ignored and an\n")
+	sb.WriteString("# empty message aborts the upload. Changes to upload:\n")
+	for _, ch := range changes {
+		sb.WriteString("#   " + ch.Status + " " + ch.Path + "\n")
 	}
 
-	Print("Following changes have been detected for", repo)
-	Print(out)
-	return nil
-}
+	f, err := ioutil.TempFile("", "logics-message-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(sb.String()); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
 
-func push(repo, msg string) error {
-	for _, args := range [][]string{
-		[]string{"add", "-A", "."},
-		[]string{"commit", "-m", msg},
-		[]string{"push", "origin", "master"},
-	} {
-		nargs := append([]string{"-C", repo}, args...)
-		out, err := common.ExecCmd("git", nargs...)
-		if err != nil {
-			return err
-		}
-		Print(out)
+	if err := editFile(f.Name()); err != nil {
+		return "", err
 	}
 
-	return nil
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(edited), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if msg = strings.TrimSpace(strings.Join(lines, "\n")); msg == "" {
+		return "", errors.New("empty commit message: upload aborted")
+	}
+	return msg, nil
 }
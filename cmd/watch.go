@@ -0,0 +1,70 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [project...]",
+	Short: "commit your work automatically every time you save it in Logic",
+	Long: `Watch the projects (all of them unless named) and commit their changes as soon as Logic is done saving (nothing changed for --settle), with a message naming the files changed. Nothing gets committed while Logic still has temporary files around. Commits are uploaded every --push-interval, if set. Press Ctrl-C to stop. For example:
+
+  logics watch                                # commit, never upload
+  logics watch capelli-curti --push-interval 30m
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		repos := cfg.Repos
+		if len(args) > 0 {
+			repos = make([]config.Repo, 0, len(args))
+			for _, name := range args {
+				i, err := cfg.FindRepo(name)
+				if err != nil {
+					return err
+				}
+				repos = append(repos, cfg.Repos[i])
+			}
+		}
+		if len(repos) == 0 {
+			return errors.New("no project installed yet. Run `logics install` first")
+		}
+
+		opts := logics.WatchOptions{}
+		opts.Settle, _ = cmd.Flags().GetDuration("settle")
+		opts.PushInterval, _ = cmd.Flags().GetDuration("push-interval")
+		client := newClient()
+		client.Ignore = cfg.Ignore
+		return client.Watch(runCtx, repos, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().Duration("settle", 10*time.Second, "how long a project must be left alone before committing")
+	watchCmd.Flags().Duration("push-interval", 0, "how often to upload the commits (e.g. 30m). Zero means never")
+}
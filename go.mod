@@ -3,11 +3,12 @@ module github.com/autholykos/logics
 go 1.13
 
 require (
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/hashicorp/go-getter v1.4.1
 	github.com/lunixbochs/vtclean v1.0.0 // indirect
 	github.com/manifoldco/promptui v0.7.0
 	github.com/mattn/go-colorable v0.1.4 // indirect
-	github.com/mattn/go-isatty v0.0.12 // indirect
+	github.com/mattn/go-isatty v0.0.12
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
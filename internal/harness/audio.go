@@ -0,0 +1,27 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// WAV renders a silent PCM WAV file
+func WAV(channels, sampleRate, bitDepth, frames int) []byte {
+	blockAlign := channels * bitDepth / 8
+	dataSize := frames * blockAlign
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(4+8+16+8+dataSize))
+	b.WriteString("WAVEfmt ")
+	for _, v := range []interface{}{
+		uint32(16), uint16(1), uint16(channels), uint32(sampleRate),
+		uint32(sampleRate * blockAlign), uint16(blockAlign), uint16(bitDepth),
+	} {
+		_ = binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(dataSize))
+	b.Write(make([]byte, dataSize))
+	return b.Bytes()
+}
@@ -0,0 +1,116 @@
+// Package harness provides the fixtures used by the tests that exercise
+// logics end-to-end without network access nor root permissions.
+//
+// git-lfs and lfs-folderstore are faked, so LFS itself is out of the scope of
+// these tests: the audio files travel as regular git blobs, while the lfs
+// filter, the folderstore transfer agent and the partial downloads
+// (lfs.fetchinclude) are only checked as far as their configuration goes
+package harness
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+)
+
+// FakeGitLFS is a stand-in for git-lfs: `git lfs install` is a no-op and
+// `git lfs track` writes the patterns to .gitattributes. Since the lfs filter
+// never gets configured, git stores the files as regular blobs
+const FakeGitLFS = `#!/bin/sh
+case "$1" in
+track)
+	shift
+	for p in "$@"; do
+		echo "$p filter=lfs diff=lfs merge=lfs -text" >> .gitattributes
+	done
+	;;
+version)
+	echo "git-lfs/fake"
+	;;
+esac
+exit 0
+`
+
+// FakeLFSFolderstore is a stand-in for lfs-folderstore. It is never invoked
+// as long as FakeGitLFS is in use
+const FakeLFSFolderstore = "#!/bin/sh\nexit 0\n"
+
+// Releases serves fake git-lfs and lfs-folderstore release archives laid out
+// like the github ones
+type Releases struct {
+	*httptest.Server
+}
+
+// NewReleases starts serving the fake release archives. Callers must Close it
+func NewReleases() (*Releases, error) {
+	lfs, err := tarGz("git-lfs", FakeGitLFS)
+	if err != nil {
+		return nil, err
+	}
+	folderstore, err := zipped("lfs-folderstore-darwin-amd64/lfs-folderstore", FakeLFSFolderstore)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/git-lfs.tar.gz", serve(lfs))
+	mux.HandleFunc("/lfs-folderstore.zip", serve(folderstore))
+	return &Releases{httptest.NewServer(mux)}, nil
+}
+
+// GitLFSURL is the location of the git-lfs archive
+func (r *Releases) GitLFSURL() string {
+	return r.URL + "/git-lfs.tar.gz"
+}
+
+// LFSFolderstoreURL is the location of the lfs-folderstore archive
+func (r *Releases) LFSFolderstoreURL() string {
+	return r.URL + "/lfs-folderstore.zip"
+}
+
+func serve(body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}
+}
+
+func tarGz(name, content string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zipped(name, content string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	hdr.SetMode(0755)
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
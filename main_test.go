@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/internal/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+// the test binary runs logics (rather than the tests) when this variable is
+// set, so that every command gets a fresh process like in real life
+const runMainEnv = "LOGICS_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// machine simulates a computer of the team, with its own home, bin and
+// project folders. The shared folder is common to every machine
+type machine struct {
+	t        *testing.T
+	env      []string
+	projects string
+}
+
+func newMachine(t *testing.T, root, name string, releases *harness.Releases) *machine {
+	home := path.Join(root, name, "home")
+	bin := path.Join(root, name, "bin")
+	projects := path.Join(root, name, "projects")
+	for _, dir := range []string{home, bin} {
+		if !assert.NoError(t, os.MkdirAll(dir, 0755)) {
+			t.FailNow()
+		}
+	}
+
+	env := []string{
+		runMainEnv + "=1",
+		"HOME=" + home,
+		"XDG_CONFIG_HOME=" + path.Join(home, ".config"),
+		"PATH=" + bin + string(os.PathListSeparator) + os.Getenv("PATH"),
+		"LOGICS_BIN_DIR=" + bin,
+		"LOGICS_GIT_LFS_URL=" + releases.GitLFSURL(),
+		"LOGICS_LFS_FOLDERSTORE_URL=" + releases.LFSFolderstoreURL(),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=" + name,
+		"GIT_AUTHOR_EMAIL=" + name + "@example.com",
+		"GIT_COMMITTER_NAME=" + name,
+		"GIT_COMMITTER_EMAIL=" + name + "@example.com",
+	}
+	return &machine{t: t, env: env, projects: projects}
+}
+
+// logics runs logics on the machine, failing the test on error. It returns
+// the standard output
+func (m *machine) logics(args ...string) string {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = m.env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		m.t.Fatalf("logics %v: %v\nstdout:\n%s\nstderr:\n%s", args, err, stdout.String(), stderr.String())
+	}
+	return stdout.String()
+}
+
+func writeFile(t *testing.T, file, content string) {
+	if !assert.NoError(t, os.MkdirAll(path.Dir(file), 0755)) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644)) {
+		t.FailNow()
+	}
+}
+
+func assertContent(t *testing.T, file, content string) {
+	b, err := ioutil.ReadFile(file)
+	if assert.NoError(t, err) {
+		assert.Equal(t, content, string(b))
+	}
+}
+
+// metaData is the metadata of a Logic alternative at the given tempo
+func metaData(bpm int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>BeatsPerMinute</key><integer>%d</integer>
+	<key>NumberOfTracks</key><integer>2</integer>
+</dict>
+</plist>`, bpm)
+}
+
+func TestTwoMachines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	releases, err := harness.NewReleases()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer releases.Close()
+
+	root, err := ioutil.TempDir("", "logics-integration")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(root)
+
+	shared := path.Join(root, "shared")
+	if !assert.NoError(t, os.MkdirAll(shared, 0755)) {
+		t.FailNow()
+	}
+
+	alice := newMachine(t, root, "alice", releases)
+	bob := newMachine(t, root, "bob", releases)
+	for _, m := range []*machine{alice, bob} {
+		m.logics("setup", "--yes", "--sharedfolder", shared, "--projectfolder", m.projects)
+	}
+
+	// alice shares her project
+	song := path.Join(alice.projects, "song")
+	take := path.Join("song.logicx", "Media", "Audio Files", "take.wav")
+	writeFile(t, path.Join(song, "song.logicx", "Alternatives", "000", "ProjectData"), "project v1")
+	writeFile(t, path.Join(song, "song.logicx", "Alternatives", "000", "MetaData.plist"), metaData(120))
+	writeFile(t, path.Join(song, take), "first take")
+	writeFile(t, path.Join(song, ".DS_Store"), "junk")
+	writeFile(t, path.Join(song, "song.logicx", "Alternatives", "000", "Autosave", "ProjectData"), "autosave")
+	alice.logics("new", song)
+	assertContent(t, path.Join(song, ".gitattributes"), "*.wav filter=lfs diff=lfs merge=lfs -text\n"+
+		"*.aif filter=lfs diff=lfs merge=lfs -text\n"+
+		"*.aiff filter=lfs diff=lfs merge=lfs -text\n"+
+		"*.mp3 filter=lfs diff=lfs merge=lfs -text\n"+
+		"*.m4a filter=lfs diff=lfs merge=lfs -text\n"+
+		"*.caf filter=lfs diff=lfs merge=lfs -text\n")
+	_, err = os.Stat(path.Join(shared, "song.git"))
+	assert.NoError(t, err)
+
+	// bob installs it and records a second take
+	bob.logics("install", "song")
+	bobSong := path.Join(bob.projects, "song")
+	assertContent(t, path.Join(bobSong, take), "first take")
+	for _, junk := range []string{".DS_Store", path.Join("song.logicx", "Alternatives", "000", "Autosave")} {
+		_, err := os.Stat(path.Join(bobSong, junk))
+		assert.True(t, os.IsNotExist(err), junk)
+	}
+
+	writeFile(t, path.Join(bobSong, take), string(harness.WAV(2, 48000, 24, 48000*3)))
+	writeFile(t, path.Join(bobSong, "song.logicx", "Alternatives", "000", "ProjectData"), "project v2")
+	writeFile(t, path.Join(bobSong, "song.logicx", "Alternatives", "000", "MetaData.plist"), metaData(124))
+	var up struct {
+		Commits int `json:"commits"`
+	}
+	writeFile(t, path.Join(bobSong, "Scratch", "idea.wav"), "scratch")
+	assert.NoError(t, json.Unmarshal([]byte(bob.logics("-o", "json", "upload", "song", "-m", "second take", "--exclude", "Scratch/")), &up))
+	assert.Equal(t, 1, up.Commits)
+
+	// alice hears about it and gets it
+	assert.Equal(t, "logics: song: 1 new commit(s) by bob changing MetaData.plist, ProjectData, take.wav. Run `logics download song` to get them\n", alice.logics("fetch", "--all"))
+	alice.logics("download", "song")
+	assert.Equal(t, "no new changes\n", alice.logics("fetch", "song"))
+	assertContent(t, path.Join(song, take), string(harness.WAV(2, 48000, 24, 48000*3)))
+	assertContent(t, path.Join(song, "song.logicx", "Alternatives", "000", "ProjectData"), "project v2")
+	_, err = os.Stat(path.Join(song, "Scratch"))
+	assert.True(t, os.IsNotExist(err))
+
+	var commits []struct {
+		Author string   `json:"author"`
+		Music  []string `json:"music"`
+		Audio  []struct {
+			Path string `json:"path"`
+			Info struct {
+				Channels   int     `json:"channels"`
+				SampleRate float64 `json:"samplerate"`
+			} `json:"info"`
+		} `json:"audio"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(alice.logics("-o", "json", "history", "song")), &commits))
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, "bob", commits[0].Author)
+		assert.Equal(t, []string{"tempo 120 → 124 BPM"}, commits[0].Music)
+		if assert.Len(t, commits[0].Audio, 1) {
+			assert.Equal(t, take, commits[0].Audio[0].Path)
+			assert.Equal(t, 2, commits[0].Audio[0].Info.Channels)
+			assert.Equal(t, 48000.0, commits[0].Audio[0].Info.SampleRate)
+		}
+		assert.Empty(t, commits[1].Audio)
+		assert.Equal(t, []string{"new song song"}, commits[1].Music)
+	}
+
+	// no song uses the take
+	var media struct {
+		Unused []struct {
+			Path string `json:"path"`
+		} `json:"unused"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(alice.logics("-o", "json", "media", "check", "song")), &media))
+	if assert.Len(t, media.Unused, 1) {
+		assert.Equal(t, take, media.Unused[0].Path)
+	}
+
+	// alice only wants the bounces, then some of the takes, then everything
+	alice.logics("download", "song", "--lite")
+	assert.Equal(t, "Bounces/**,**/Bounces/**\n", alice.logics("config", "get", "repos.song.fetchinclude"))
+	include, err := exec.Command("git", "-C", song, "config", "--get", "lfs.fetchinclude").Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bounces/**,**/Bounces/**\n", string(include))
+	alice.logics("fetch-media", "--project", "song", "**/Audio Files/**")
+	alice.logics("download", "song", "--full")
+	assert.Error(t, exec.Command("git", "-C", song, "config", "--get", "lfs.fetchinclude").Run())
+
+	var projects []struct {
+		Name    string `json:"name"`
+		Changes int    `json:"changes"`
+		Ahead   int    `json:"ahead"`
+		Behind  int    `json:"behind"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(alice.logics("-o", "json", "list")), &projects))
+	if assert.Len(t, projects, 1) {
+		assert.Equal(t, "song", projects[0].Name)
+		assert.Equal(t, 0, projects[0].Changes+projects[0].Ahead+projects[0].Behind)
+	}
+}
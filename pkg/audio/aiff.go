@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// readAIFF reads the chunks of an AIFF or AIFC file. They carry no recording
+// metadata
+func readAIFF(r io.ReadSeeker, form string) (*Info, error) {
+	info := &Info{Format: form}
+	var hasCommon bool
+
+	err := walkChunks(r, binary.BigEndian, func(id string, size int64, body io.Reader) error {
+		if id != "COMM" {
+			return nil
+		}
+
+		var comm struct {
+			Channels   int16
+			Frames     uint32
+			SampleSize int16
+			SampleRate [10]byte
+		}
+		if err := binary.Read(body, binary.BigEndian, &comm); err != nil {
+			return ErrFormat
+		}
+		hasCommon = true
+		info.Channels, info.Frames, info.BitDepth = int(comm.Channels), int64(comm.Frames), int(comm.SampleSize)
+		info.SampleRate = extended(comm.SampleRate)
+
+		// AIFC tells the compression right after
+		var compression [4]byte
+		if form == "AIFC" && binary.Read(body, binary.BigEndian, &compression) == nil {
+			switch string(compression[:]) {
+			case "fl32", "FL32", "fl64", "FL64":
+				info.Float = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasCommon {
+		return nil, ErrFormat
+	}
+	info.Duration = duration(info.Frames, info.SampleRate)
+	return info, nil
+}
+
+// extended decodes an 80 bit IEEE 754 extended precision number, which AIFF
+// stores the sample rate as
+func extended(b [10]byte) float64 {
+	exponent := int(binary.BigEndian.Uint16(b[0:2]))
+	mantissa := binary.BigEndian.Uint64(b[2:10])
+	if exponent&0x7fff == 0 && mantissa == 0 {
+		return 0
+	}
+
+	f := math.Ldexp(float64(mantissa), exponent&0x7fff-16383-63)
+	if exponent&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -0,0 +1,160 @@
+// Package audio reads the headers of WAV (including the broadcast wave bext
+// and iXML chunks) and AIFF/AIFC files: format, duration and, when the
+// recorder wrote them, when and by what the audio was recorded. The samples
+// are never read
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFormat is returned for data which is not a WAV or AIFF file
+var ErrFormat = errors.New("not a WAV or AIFF file")
+
+// Info describes an audio file
+type Info struct {
+	// Format is WAV, AIFF or AIFC
+	Format     string        `json:"format"`
+	Channels   int           `json:"channels"`
+	SampleRate float64       `json:"samplerate"`
+	BitDepth   int           `json:"bitdepth"`
+	Float      bool          `json:"float,omitempty"`
+	Frames     int64         `json:"frames"`
+	Duration   time.Duration `json:"duration"`
+
+	// Description, Originator, Recorded and TimeReference (the samples
+	// since midnight the recording started at) come from the bext chunk
+	Description   string     `json:"description,omitempty"`
+	Originator    string     `json:"originator,omitempty"`
+	Recorded      *time.Time `json:"recorded,omitempty"`
+	TimeReference uint64     `json:"timereference,omitempty"`
+
+	// Project, Scene, Take and Note come from the iXML chunk
+	Project string `json:"project,omitempty"`
+	Scene   string `json:"scene,omitempty"`
+	Take    string `json:"take,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// String summarizes the audio, e.g. "0:42, stereo, 48 kHz, 24-bit, recorded
+// 2020-05-01 12:00:00"
+func (i *Info) String() string {
+	depth := fmt.Sprintf("%d-bit", i.BitDepth)
+	if i.Float {
+		depth += " float"
+	}
+	parts := []string{formatDuration(i.Duration), formatChannels(i.Channels), formatSampleRate(i.SampleRate), depth}
+	if i.Recorded != nil {
+		parts = append(parts, "recorded "+i.Recorded.Format("2006-01-02 15:04:05"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Supported tells whether the name of a file has the extension of the
+// formats Read understands
+func Supported(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".wav", ".wave", ".bwf", ".aif", ".aiff", ".aifc":
+		return true
+	}
+	return false
+}
+
+// ReadFile reads the headers of the audio file name
+func ReadFile(name string) (*Info, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads the headers of the audio in r, skipping the samples
+func Read(r io.ReadSeeker) (*Info, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, ErrFormat
+	}
+
+	container, form := string(header[0:4]), string(header[8:12])
+	switch {
+	case container == "RIFF" && form == "WAVE":
+		return readWAV(r)
+	case container == "FORM" && (form == "AIFF" || form == "AIFC"):
+		return readAIFF(r, form)
+	}
+	return nil, ErrFormat
+}
+
+// walkChunks calls fn with every chunk from the current offset of r on. fn
+// gets the body of the chunk limited to its size, and does not need to read
+// it all. A truncated last chunk ends the walk
+func walkChunks(r io.ReadSeeker, order binary.ByteOrder, fn func(id string, size int64, body io.Reader) error) error {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil
+		}
+
+		size := int64(order.Uint32(header[4:8]))
+		if err := fn(string(header[0:4]), size, io.LimitReader(r, size)); err != nil {
+			return err
+		}
+
+		// chunks are padded to an even size
+		offset += 8 + size + size&1
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+}
+
+// duration of frames played at rate
+func duration(frames int64, rate float64) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(frames) / rate * float64(time.Second))
+}
+
+// formatDuration renders d as m:ss, or h:mm:ss from an hour on
+func formatDuration(d time.Duration) string {
+	s := int64(d.Round(time.Second) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+func formatChannels(n int) string {
+	switch n {
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	}
+	return fmt.Sprintf("%d channels", n)
+}
+
+func formatSampleRate(rate float64) string {
+	return strconv.FormatFloat(rate/1000, 'f', -1, 64) + " kHz"
+}
+
+// text trims the padding of fixed size text fields
+func text(b []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
@@ -0,0 +1,171 @@
+package audio_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autholykos/logics/pkg/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+// chunk renders a chunk, padded to an even size
+func chunk(order binary.ByteOrder, id string, body []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(id)
+	_ = binary.Write(&b, order, uint32(len(body)))
+	b.Write(body)
+	if len(body)%2 == 1 {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+func wav(channels, rate, bits, frames int, chunks ...[]byte) []byte {
+	var format bytes.Buffer
+	blockAlign := channels * bits / 8
+	for _, v := range []interface{}{uint16(1), uint16(channels), uint32(rate), uint32(rate * blockAlign), uint16(blockAlign), uint16(bits)} {
+		_ = binary.Write(&format, binary.LittleEndian, v)
+	}
+
+	body := []byte("WAVE")
+	body = append(body, chunk(binary.LittleEndian, "fmt ", format.Bytes())...)
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	body = append(body, chunk(binary.LittleEndian, "data", make([]byte, frames*blockAlign))...)
+	return append(chunk(binary.LittleEndian, "RIFF", body)[:8], body...)
+}
+
+func bext(description, originator, date, clock string, timeReference uint64) []byte {
+	field := func(s string, n int) []byte {
+		b := make([]byte, n)
+		copy(b, s)
+		return b
+	}
+
+	var b bytes.Buffer
+	b.Write(field(description, 256))
+	b.Write(field(originator, 32))
+	b.Write(field("", 32))
+	b.Write(field(date, 10))
+	b.Write(field(clock, 8))
+	_ = binary.Write(&b, binary.LittleEndian, timeReference)
+	b.Write(make([]byte, 256))
+	return chunk(binary.LittleEndian, "bext", b.Bytes())
+}
+
+func aiff(form string, channels, frames, bits int, rate [10]byte, extra string) []byte {
+	var comm bytes.Buffer
+	_ = binary.Write(&comm, binary.BigEndian, int16(channels))
+	_ = binary.Write(&comm, binary.BigEndian, uint32(frames))
+	_ = binary.Write(&comm, binary.BigEndian, int16(bits))
+	comm.Write(rate[:])
+	comm.WriteString(extra)
+
+	body := []byte(form)
+	body = append(body, chunk(binary.BigEndian, "COMM", comm.Bytes())...)
+	body = append(body, chunk(binary.BigEndian, "SSND", make([]byte, 8+frames*channels*bits/8))...)
+	return append(chunk(binary.BigEndian, "FORM", body)[:8], body...)
+}
+
+// 44100 and 48000 as 80 bit extended precision numbers
+var (
+	rate44k = [10]byte{0x40, 0x0e, 0xac, 0x44}
+	rate48k = [10]byte{0x40, 0x0e, 0xbb, 0x80}
+)
+
+func TestReadWAV(t *testing.T) {
+	info, err := audio.Read(bytes.NewReader(wav(2, 48000, 24, 96000)))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "WAV", info.Format)
+	assert.Equal(t, 2, info.Channels)
+	assert.Equal(t, 48000.0, info.SampleRate)
+	assert.Equal(t, 24, info.BitDepth)
+	assert.Equal(t, int64(96000), info.Frames)
+	assert.Equal(t, 2*time.Second, info.Duration)
+	assert.Nil(t, info.Recorded)
+	assert.Equal(t, "0:02, stereo, 48 kHz, 24-bit", info.String())
+}
+
+func TestReadBWF(t *testing.T) {
+	ixml := `<?xml version="1.0" encoding="UTF-8"?>
+<BWFXML><PROJECT>capelli corti</PROJECT><SCENE>vox</SCENE><TAKE>4</TAKE><NOTE>keeper</NOTE></BWFXML>`
+	data := wav(1, 44100, 16, 44100,
+		bext("lead vocals", "Logic Pro X", "2020-05-01", "12:30:15", 44100*3600),
+		chunk(binary.LittleEndian, "iXML", []byte(ixml)),
+	)
+
+	info, err := audio.Read(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "lead vocals", info.Description)
+	assert.Equal(t, "Logic Pro X", info.Originator)
+	assert.Equal(t, uint64(44100*3600), info.TimeReference)
+	assert.Equal(t, "capelli corti", info.Project)
+	assert.Equal(t, "vox", info.Scene)
+	assert.Equal(t, "4", info.Take)
+	assert.Equal(t, "keeper", info.Note)
+	assert.Equal(t, "0:01, mono, 44.1 kHz, 16-bit, recorded 2020-05-01 12:30:15", info.String())
+}
+
+func TestReadBWFOtherSeparators(t *testing.T) {
+	data := wav(1, 44100, 16, 0, bext("", "", "2020:05:01", "12-30-15", 0))
+	info, err := audio.Read(bytes.NewReader(data))
+	if !assert.NoError(t, err) || !assert.NotNil(t, info.Recorded) {
+		t.FailNow()
+	}
+	assert.Equal(t, "2020-05-01 12:30:15", info.Recorded.Format("2006-01-02 15:04:05"))
+}
+
+func TestReadAIFF(t *testing.T) {
+	info, err := audio.Read(bytes.NewReader(aiff("AIFF", 2, 44100*90, 16, rate44k, "")))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "AIFF", info.Format)
+	assert.Equal(t, 44100.0, info.SampleRate)
+	assert.Equal(t, "1:30, stereo, 44.1 kHz, 16-bit", info.String())
+
+	info, err = audio.Read(bytes.NewReader(aiff("AIFC", 6, 48000, 32, rate48k, "fl32\x00")))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "AIFC", info.Format)
+	assert.Equal(t, "0:01, 6 channels, 48 kHz, 32-bit float", info.String())
+}
+
+func TestReadNotAudio(t *testing.T) {
+	for _, data := range []string{"", "RIFF", "RIFF\x04\x00\x00\x00WAVE", "FORM\x00\x00\x00\x04AIFF", "version https://git-lfs.github.com/spec/v1\n"} {
+		_, err := audio.Read(strings.NewReader(data))
+		assert.Equal(t, audio.ErrFormat, err, "%q", data)
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	// the header of a recording in progress is readable already
+	data := wav(2, 48000, 24, 48000)
+	info, err := audio.Read(bytes.NewReader(data[:len(data)-1000]))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.Channels)
+
+	// as long as fmt made it
+	for i := 0; i < 36; i++ {
+		_, err := audio.Read(bytes.NewReader(data[:i]))
+		assert.Error(t, err)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, audio.Supported("Audio Files/Vox#01.WAV"))
+	assert.True(t, audio.Supported("Bounces/mix.aif"))
+	assert.False(t, audio.Supported("Bounces/mix.mp3"))
+	assert.False(t, audio.Supported("song.logicx"))
+}
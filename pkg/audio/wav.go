@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const (
+	formatPCM        = 1
+	formatFloat      = 3
+	formatExtensible = 0xfffe
+
+	// bextSize is the size of the fields of the bext chunk read, up to
+	// the time reference
+	bextSize = 256 + 32 + 32 + 10 + 8 + 8
+	// maxIXML bounds the size of the iXML chunk read
+	maxIXML = 1 << 20
+)
+
+// readWAV reads the chunks of a RIFF WAVE file
+func readWAV(r io.ReadSeeker) (*Info, error) {
+	info := &Info{Format: "WAV"}
+	var blockAlign int
+	var dataSize int64
+	var hasFormat bool
+
+	err := walkChunks(r, binary.LittleEndian, func(id string, size int64, body io.Reader) error {
+		switch id {
+		case "fmt ":
+			var f struct {
+				Tag        uint16
+				Channels   uint16
+				SampleRate uint32
+				ByteRate   uint32
+				BlockAlign uint16
+				Bits       uint16
+			}
+			if err := binary.Read(body, binary.LittleEndian, &f); err != nil {
+				return ErrFormat
+			}
+			hasFormat = true
+			info.Channels, info.SampleRate, info.BitDepth = int(f.Channels), float64(f.SampleRate), int(f.Bits)
+			info.Float = f.Tag == formatFloat
+			blockAlign = int(f.BlockAlign)
+
+			// the extensible format carries the actual bit depth and
+			// format after the basic fields
+			var ext struct {
+				Size      uint16
+				ValidBits uint16
+				Mask      uint32
+				SubFormat uint16
+			}
+			if f.Tag == formatExtensible && binary.Read(body, binary.LittleEndian, &ext) == nil {
+				if ext.ValidBits > 0 {
+					info.BitDepth = int(ext.ValidBits)
+				}
+				info.Float = ext.SubFormat == formatFloat
+			}
+
+		case "data":
+			dataSize = size
+
+		case "bext":
+			buf := make([]byte, bextSize)
+			if _, err := io.ReadFull(body, buf); err != nil {
+				// a broken bext chunk does not make the audio unreadable
+				return nil
+			}
+			readBext(info, buf)
+
+		case "iXML":
+			if size > maxIXML {
+				return nil
+			}
+			buf, err := ioutil.ReadAll(body)
+			if err == nil {
+				readIXML(info, buf)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasFormat {
+		return nil, ErrFormat
+	}
+	if blockAlign > 0 {
+		info.Frames = dataSize / int64(blockAlign)
+	}
+	info.Duration = duration(info.Frames, info.SampleRate)
+	return info, nil
+}
+
+// readBext reads the broadcast wave extension: description, originator,
+// origination date and time and time reference
+func readBext(info *Info, b []byte) {
+	info.Description = text(b[0:256])
+	info.Originator = text(b[256:288])
+	date, clock := text(b[320:330]), text(b[330:338])
+	info.TimeReference = binary.LittleEndian.Uint64(b[338:346])
+
+	// the standard allows any separator, e.g. 2020:05:01 or 12-00-00
+	normalize := func(s string, sep rune) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return sep
+		}, s)
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", normalize(date, '-')+" "+normalize(clock, ':'), time.Local); err == nil {
+		info.Recorded = &t
+	} else if t, err := time.ParseInLocation("2006-01-02", normalize(date, '-'), time.Local); err == nil {
+		info.Recorded = &t
+	}
+}
+
+// readIXML reads the production metadata field recorders write
+func readIXML(info *Info, b []byte) {
+	var doc struct {
+		Project string `xml:"PROJECT"`
+		Scene   string `xml:"SCENE"`
+		Take    string `xml:"TAKE"`
+		Note    string `xml:"NOTE"`
+	}
+	if err := xml.Unmarshal([]byte(text(b)), &doc); err != nil {
+		return
+	}
+	info.Project = strings.TrimSpace(doc.Project)
+	info.Scene = strings.TrimSpace(doc.Scene)
+	info.Take = strings.TrimSpace(doc.Take)
+	info.Note = strings.TrimSpace(doc.Note)
+}
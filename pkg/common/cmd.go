@@ -3,10 +3,16 @@ package common
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -23,44 +29,159 @@ const (
 	UnexpectedErr
 )
 
-// ExecErr is the error triggered by the execution of a command. It carry the
-// ErrType and the error message
+func (t ErrType) String() string {
+	switch t {
+	case NotFoundErr:
+		return "not-found"
+	case RuntimeErr:
+		return "runtime"
+	default:
+		return "unexpected"
+	}
+}
+
+// MarshalText renders the ErrType by name, e.g. in JSON output
+func (t ErrType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// ExecErr is the error triggered by the execution of a command. It carries
+// the ErrType, the Reason of the failure (when it could be recognized) and
+// everything needed to troubleshoot it. Use errors.As to retrieve it from
+// wrapped errors
 type ExecErr struct {
-	Type ErrType
-	msg  string
+	Type     ErrType
+	Reason   Reason
+	Cmd      string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Duration time.Duration
+	msg      string
 }
 
 func (e *ExecErr) Error() string {
 	return e.msg
 }
 
-var notFoundErr = &ExecErr{NotFoundErr, "command not found"}
+// Explanation describes the failure in human terms, or returns an empty
+// string if the failure was not recognized
+func (e *ExecErr) Explanation() string {
+	return hints[e.Reason].explanation
+}
+
+// Suggestion tells what to do about the failure, or returns an empty string
+// if the failure was not recognized
+func (e *ExecErr) Suggestion() string {
+	return hints[e.Reason].suggestion
+}
 
 const defaultFailedCode = -1
 
+// killGrace is how long an interrupted command gets to clean up before being
+// killed
+var killGrace = 10 * time.Second
+
+// Stream identifies the output a line was written to
+type Stream uint8
+
+const (
+	// Stdout is the standard output of a command
+	Stdout Stream = iota
+	// Stderr is the standard error of a command
+	Stderr
+)
+
+// ExecOptions tune the execution of a command
+type ExecOptions struct {
+	// Dir is the working directory of the command. Empty means the current
+	// directory
+	Dir string
+	// Env is appended to the environment of the current process
+	Env []string
+	// Timeout interrupts the command once expired. Zero means no timeout
+	Timeout time.Duration
+	// OnLine, if set, gets called with every line the command writes, as
+	// soon as it is written. Carriage returns (used by progress meters) end
+	// a line too
+	OnLine func(stream Stream, line string)
+}
+
 // ExecCmd executes a command and returns the exitcode as well as the stdout
 // and stderr of the command executed. Returns the stdout or an ExecErr
 // wrapping the stderr
 func ExecCmd(name string, args ...string) (string, error) {
-	// first we check if there is a git installation already
-	stdout, stderr, err := runcmd(name, args...)
+	return ExecCmdContext(context.Background(), ExecOptions{}, name, args...)
+}
+
+// ExecCmdContext is like ExecCmd, but the command gets interrupted (and
+// killed if it does not exit within a grace period) when ctx is done or the
+// timeout in opts expires. Interrupting rather than killing lets git clean up
+// after itself
+func ExecCmdContext(ctx context.Context, opts ExecOptions, name string, args ...string) (string, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	stdout, stderr, err := runcmd(ctx, opts, name, args...)
+	duration := time.Since(start)
+	exitCode := extractExitCode(err)
+	log.WithFields(log.Fields{
+		"cmd":      cmdline(name, args...),
+		"duration": duration.Round(time.Millisecond),
+		"exitcode": exitCode,
+	}).Infoln("command executed")
+
 	if err != nil {
 		log.WithError(err).WithField("name", name).Debugln("command triggered an error")
-		exitCode := extractExitCode(err)
-		if exitCode == defaultFailedCode {
-			return "", notFoundErr
+		e := &ExecErr{
+			Cmd:      cmdline(name, args...),
+			ExitCode: exitCode,
+			Stdout:   string(stdout),
+			Stderr:   string(stderr),
+			Duration: duration,
 		}
 
-		if len(stderr) > 0 {
-			return "", &ExecErr{RuntimeErr, string(stderr)}
+		switch {
+		case isNotFound(err):
+			e.Type, e.msg = NotFoundErr, fmt.Sprintf("command not found: %s", name)
+		case ctx.Err() == context.DeadlineExceeded:
+			e.Type, e.Reason = RuntimeErr, TimedOut
+			e.msg = fmt.Sprintf("%s timed out after %v", name, duration.Round(time.Second))
+		case ctx.Err() == context.Canceled:
+			e.Type, e.Reason = RuntimeErr, Interrupted
+			e.msg = fmt.Sprintf("%s interrupted", name)
+		case exitCode == defaultFailedCode:
+			e.Type, e.msg = UnexpectedErr, err.Error()
+		case len(stderr) > 0:
+			e.Type, e.msg = RuntimeErr, string(stderr)
+		default:
+			e.Type, e.msg = UnexpectedErr, err.Error()
 		}
 
-		return "", &ExecErr{UnexpectedErr, err.Error()}
+		if e.Reason == UnknownReason {
+			e.Reason = classify(e.Stdout + e.Stderr)
+		}
+		return "", e
 	}
 
 	return string(stdout), nil
 }
 
+// isNotFound tells whether the command could not be started because it is not
+// available (either not in $PATH or not at the given location)
+func isNotFound(err error) bool {
+	if errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+
+	var perr *os.PathError
+	return errors.As(err, &perr) && os.IsNotExist(perr.Err)
+}
+
 // extractExitCode from the error passed
 func extractExitCode(err error) int {
 	// base case
@@ -74,35 +195,106 @@ func extractExitCode(err error) int {
 		return ws.ExitStatus()
 	}
 
-	// This will happen (in OSX) if `name` is not available in $PATH,
-	// in this situation, exit code could not be get, and stderr will be
-	// empty string very likely, so we use the default fail code, and format err
-	// to string and set to stderr
+	// the command could not be started at all (e.g. `name` is not
+	// available in $PATH), so there is no exit code to get
 	return defaultFailedCode
 }
 
 // runcmd executes a command and returns the stdout, stderr and an eventual
 // error
-func runcmd(name string, args ...string) ([]byte, []byte, error) {
-	var sb strings.Builder
-	sb.WriteString(name)
-	sb.WriteString(" ")
-	for i, arg := range args {
-		if i != 0 {
-			sb.WriteString(" ")
-		}
-		sb.WriteString(arg)
-	}
-
-	log.Debugln(fmt.Sprintf("running cmd: `%s`", sb.String()))
+func runcmd(ctx context.Context, opts ExecOptions, name string, args ...string) ([]byte, []byte, error) {
+	log.Debugln(fmt.Sprintf("running cmd: `%s`", cmdline(name, args...)))
 	var outbuf, errbuf bytes.Buffer
 	cmd := exec.Command(name, args...)
+	cmd.Dir = opts.Dir
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
 	cmd.Stdout = &outbuf
 	cmd.Stderr = &errbuf
 
-	if err := cmd.Run(); err != nil {
+	if opts.OnLine != nil {
+		// exec copies stdout and stderr from two goroutines
+		var mu sync.Mutex
+		outlines := &lineWriter{stream: Stdout, onLine: opts.OnLine, mu: &mu}
+		errlines := &lineWriter{stream: Stderr, onLine: opts.OnLine, mu: &mu}
+		defer outlines.Flush()
+		defer errlines.Flush()
+		cmd.Stdout = io.MultiWriter(&outbuf, outlines)
+		cmd.Stderr = io.MultiWriter(&errbuf, errlines)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		log.WithField("cmd", name).Debugln("interrupting command")
+		_ = cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-done:
+		case <-time.After(killGrace):
+			log.WithField("cmd", name).Warnln("command did not exit after being interrupted, killing it")
+			_ = cmd.Process.Kill()
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
 		return outbuf.Bytes(), errbuf.Bytes(), err
 	}
 
 	return outbuf.Bytes(), errbuf.Bytes(), nil
 }
+
+// lineWriter splits what gets written into lines and hands them over to
+// onLine. Lines end either with a newline or with a carriage return
+type lineWriter struct {
+	stream Stream
+	onLine func(Stream, string)
+	// mu serializes the calls to onLine shared with the other stream
+	mu  *sync.Mutex
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' || b == '\r' {
+			w.Flush()
+			continue
+		}
+		w.buf = append(w.buf, b)
+	}
+	return len(p), nil
+}
+
+// Flush hands over the pending partial line, if any
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.mu.Lock()
+		w.onLine(w.stream, string(w.buf))
+		w.mu.Unlock()
+		w.buf = w.buf[:0]
+	}
+}
+
+// cmdline renders a command and its arguments for logging
+func cmdline(name string, args ...string) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteString(" ")
+	for i, arg := range args {
+		if i != 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(arg)
+	}
+	return sb.String()
+}
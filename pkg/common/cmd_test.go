@@ -0,0 +1,104 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecCmd(t *testing.T) {
+	out, err := common.ExecCmd("sh", "-c", "echo hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", out)
+}
+
+func TestExecCmdNotFound(t *testing.T) {
+	_, err := common.ExecCmd("surely-not-a-logics-command")
+
+	var execErr *common.ExecErr
+	if !assert.True(t, errors.As(err, &execErr)) {
+		t.FailNow()
+	}
+	assert.Equal(t, common.NotFoundErr, execErr.Type)
+}
+
+func TestExecCmdFailure(t *testing.T) {
+	_, err := common.ExecCmd("sh", "-c", "echo out; echo 'fatal: not a git repository' >&2; exit 128")
+	err = fmt.Errorf("wrapped: %w", err)
+
+	var execErr *common.ExecErr
+	if !assert.True(t, errors.As(err, &execErr)) {
+		t.FailNow()
+	}
+	assert.Equal(t, common.RuntimeErr, execErr.Type)
+	assert.Equal(t, common.NotARepo, execErr.Reason)
+	assert.Equal(t, 128, execErr.ExitCode)
+	assert.Equal(t, "out\n", execErr.Stdout)
+	assert.Contains(t, execErr.Cmd, "exit 128")
+	assert.NotEmpty(t, execErr.Suggestion())
+}
+
+func TestClassification(t *testing.T) {
+	for stderr, reason := range map[string]common.Reason{
+		" ! [rejected]        master -> master (fetch first)":                            common.NonFastForward,
+		"error: Your local changes to the following files would be overwritten by merge": common.DirtyTree,
+		"CONFLICT (content): Merge conflict in song.logicx":                              common.MergeConflict,
+		"write error: No space left on device":                                           common.DiskFull,
+		"Error downloading object: Vox.wav: Object does not exist":                       common.MissingLFSObject,
+		"fatal: could not open 'song.logicx/ProjectData': Operation not permitted":       common.FilePermission,
+		"remote: Permission denied":                                                      common.AuthFailed,
+		"something else entirely":                                                        common.UnknownReason,
+	} {
+		_, err := common.ExecCmd("sh", "-c", fmt.Sprintf("echo '%s' >&2; exit 1", stderr))
+
+		var execErr *common.ExecErr
+		if assert.True(t, errors.As(err, &execErr)) {
+			assert.Equal(t, reason, execErr.Reason, stderr)
+		}
+	}
+}
+
+func TestExecCmdContextStreaming(t *testing.T) {
+	lines := make([]string, 0)
+	opts := common.ExecOptions{
+		OnLine: func(stream common.Stream, line string) {
+			lines = append(lines, fmt.Sprintf("%d:%s", stream, line))
+		},
+	}
+
+	out, err := common.ExecCmdContext(context.Background(), opts, "sh", "-c", `printf 'one\ntwo'; printf '10%%\r20%%\r' >&2`)
+	assert.NoError(t, err)
+	assert.Equal(t, "one\ntwo", out)
+	assert.ElementsMatch(t, []string{"0:one", "0:two", "1:10%", "1:20%"}, lines)
+}
+
+func TestExecCmdContextTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := common.ExecCmdContext(context.Background(), common.ExecOptions{Timeout: 100 * time.Millisecond}, "sleep", "5")
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	var execErr *common.ExecErr
+	if assert.True(t, errors.As(err, &execErr)) {
+		assert.Equal(t, common.TimedOut, execErr.Reason)
+	}
+}
+
+func TestExecCmdContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := common.ExecCmdContext(ctx, common.ExecOptions{}, "sleep", "5")
+
+	var execErr *common.ExecErr
+	if assert.True(t, errors.As(err, &execErr)) {
+		assert.Equal(t, common.Interrupted, execErr.Reason)
+	}
+}
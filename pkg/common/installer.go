@@ -12,34 +12,50 @@ import (
 	"github.com/hashicorp/go-getter"
 )
 
-var gitLFSReleaseURL = "https://github.com/git-lfs/git-lfs/releases/download/v2.10.0/git-lfs-darwin-amd64-v2.10.0.tar.gz"
-var lfsFolderstoreURL = "https://github.com/sinbad/lfs-folderstore/releases/download/v1.0.0/lfs-folderstore-darwin-amd64-v1.0.0.zip"
+// The release locations and the folder dependencies get installed into can be
+// overridden through the environment, e.g. to use a mirror or to install
+// without root permissions
+var (
+	// GitLFSReleaseURL is the git-lfs release archive
+	GitLFSReleaseURL = envOr("LOGICS_GIT_LFS_URL", "https://github.com/git-lfs/git-lfs/releases/download/v2.10.0/git-lfs-darwin-amd64-v2.10.0.tar.gz")
+	// LFSFolderstoreURL is the lfs-folderstore release archive
+	LFSFolderstoreURL = envOr("LOGICS_LFS_FOLDERSTORE_URL", "https://github.com/sinbad/lfs-folderstore/releases/download/v1.0.0/lfs-folderstore-darwin-amd64-v1.0.0.zip")
+	// BinDir is the folder (within the $PATH) the dependencies get moved to
+	BinDir = envOr("LOGICS_BIN_DIR", "/usr/local/bin")
+)
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
 
 // InstallGitLFS downloads the git-lfs package from github and installs it by
-// moving it on the path (/usr/local/bin folder)
+// moving it on the path (BinDir)
 func InstallGitLFS(tmpDir string) error {
 	// for some reason GitLFS package gets installed on the tmp folder
 	// bypassing the package name. We work around that by adding a folder to
 	// the tmpDir
 	baseDir := path.Join(tmpDir, "git-lfs.pkg")
 	log.WithField("tmpDir", baseDir).Debugln("downloading the git-lfs package")
-	if err := Install(gitLFSReleaseURL, "", "git-lfs", baseDir); err != nil {
+	if err := Install(GitLFSReleaseURL, "", "git-lfs", baseDir); err != nil {
 		return fmt.Errorf("error in installing git-lfs: %v", err)
 	}
 
 	log.Debugln("executing `git lfs install`")
 	if _, err := ExecCmd("git", "lfs", "install"); err != nil {
-		return fmt.Errorf("error in executing `git lfs install`: %v", err)
+		return fmt.Errorf("error in executing `git lfs install`: %w", err)
 	}
 
 	log.Debugln("git-lfs successfully installed")
 	return nil
 }
 
-// InstallLFSFolderstore downloads the git-lfs package from github and installs it by
-// moving it on the path (/usr/local/bin folder)
+// InstallLFSFolderstore downloads the lfs-folderstore package from github and
+// installs it by moving it on the path (BinDir)
 func InstallLFSFolderstore(tmpDir string) error {
-	if err := Install(lfsFolderstoreURL, "lfs-folderstore-darwin-amd64", "lfs-folderstore", tmpDir); err != nil {
+	if err := Install(LFSFolderstoreURL, "lfs-folderstore-darwin-amd64", "lfs-folderstore", tmpDir); err != nil {
 		return fmt.Errorf("error in installing lfs-folderstore: %v", err)
 	}
 
@@ -47,7 +63,7 @@ func InstallLFSFolderstore(tmpDir string) error {
 }
 
 // Install downloads a package, decompress it and moves it into the path (at
-// /usr/local/bin)
+// BinDir)
 func Install(srcURL, pack, name, tmpDir string) error {
 	log.WithFields(log.Fields{
 		"tmp-dir": tmpDir,
@@ -84,8 +100,8 @@ func Install(srcURL, pack, name, tmpDir string) error {
 		return errors.New("download failed")
 	}
 
-	if _, err := ExecCmd("/bin/mv", artifact, "/usr/local/bin/"); err != nil {
-		return fmt.Errorf("error in moving %s to /usr/local/bin: %v", artifact, err)
+	if _, err := ExecCmd("/bin/mv", artifact, BinDir+"/"); err != nil {
+		return fmt.Errorf("error in moving %s to %s: %w", artifact, BinDir, err)
 	}
 
 	return nil
@@ -1,38 +1,67 @@
 package common_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path"
 	"testing"
 
+	"github.com/autholykos/logics/internal/harness"
 	"github.com/autholykos/logics/pkg/common"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGitLFSInstall(t *testing.T) {
-	defer os.RemoveAll("/tmp/logics")
-
-	if !assert.NoError(t, os.MkdirAll("/tmp/logics", 0755)) {
+// withFakeReleases points the installer to a local server and a temporary
+// bin folder (added to the $PATH). It returns the bin folder and a function
+// restoring the previous settings
+func withFakeReleases(t *testing.T) (string, func()) {
+	releases, err := harness.NewReleases()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	dir, err := ioutil.TempDir("", "logics")
+	if !assert.NoError(t, err) {
 		t.FailNow()
 	}
-	if !assert.NoError(t, common.InstallGitLFS("/tmp/logics")) {
+	binDir := path.Join(dir, "bin")
+	if !assert.NoError(t, os.MkdirAll(binDir, 0755)) {
 		t.FailNow()
 	}
 
-	_, err := os.Stat("/usr/local/bin/git-lfs")
-	assert.NoError(t, err)
+	gitLFS, folderstore, bin, p := common.GitLFSReleaseURL, common.LFSFolderstoreURL, common.BinDir, os.Getenv("PATH")
+	common.GitLFSReleaseURL = releases.GitLFSURL()
+	common.LFSFolderstoreURL = releases.LFSFolderstoreURL()
+	common.BinDir = binDir
+	_ = os.Setenv("PATH", binDir+string(os.PathListSeparator)+p)
+
+	return dir, func() {
+		common.GitLFSReleaseURL, common.LFSFolderstoreURL, common.BinDir = gitLFS, folderstore, bin
+		_ = os.Setenv("PATH", p)
+		releases.Close()
+		_ = os.RemoveAll(dir)
+	}
 }
 
-func TestLFSFolderstoreInstall(t *testing.T) {
-	defer os.RemoveAll("/tmp/logics")
+func TestGitLFSInstall(t *testing.T) {
+	dir, restore := withFakeReleases(t)
+	defer restore()
 
-	if !assert.NoError(t, os.MkdirAll("/tmp/logics", 0755)) {
+	if !assert.NoError(t, common.InstallGitLFS(path.Join(dir, "tmp"))) {
 		t.FailNow()
 	}
-	if !assert.NoError(t, common.InstallLFSFolderstore("/tmp/logics")) {
 
+	_, err := os.Stat(path.Join(common.BinDir, "git-lfs"))
+	assert.NoError(t, err)
+}
+
+func TestLFSFolderstoreInstall(t *testing.T) {
+	dir, restore := withFakeReleases(t)
+	defer restore()
+
+	if !assert.NoError(t, common.InstallLFSFolderstore(path.Join(dir, "tmp"))) {
 		t.FailNow()
 	}
 
-	_, err := os.Stat("/usr/local/bin/lfs-folderstore")
+	_, err := os.Stat(path.Join(common.BinDir, "lfs-folderstore"))
 	assert.NoError(t, err)
 }
@@ -0,0 +1,188 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// logFileName is the name of the log file within the log folder
+	logFileName = "logics.log"
+	// maxLogSize is the size after which the log file gets rotated
+	maxLogSize = 5 * 1024 * 1024
+	// maxLogFiles is the number of rotated log files kept around
+	maxLogFiles = 5
+)
+
+// LogOptions configure where logs go and how verbose they are
+type LogOptions struct {
+	// Level is the minimum level printed on the console
+	Level log.Level
+	// File is the log file, which always receives every log entry. An empty
+	// File disables logging to file
+	File string
+}
+
+// LogDir returns the folder where logics keeps its log files
+func LogDir() (string, error) {
+	hd, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(hd, ".logics", "logs"), nil
+}
+
+// DefaultLogFile returns the path of the log file within LogDir
+func DefaultLogFile() (string, error) {
+	dir, err := LogDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, logFileName), nil
+}
+
+// SetupLogging routes log entries to the console (filtered by level) and to
+// the rotating log file (unfiltered)
+func SetupLogging(opts LogOptions) error {
+	log.SetLevel(log.DebugLevel)
+	log.SetOutput(ioutil.Discard)
+	log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+
+	log.AddHook(&writerHook{
+		w:         os.Stderr,
+		levels:    levelsUpTo(opts.Level),
+		formatter: &log.TextFormatter{},
+	})
+
+	if opts.File == "" {
+		return nil
+	}
+
+	f, err := NewRotatingFile(opts.File, maxLogSize, maxLogFiles)
+	if err != nil {
+		return fmt.Errorf("could not open the log file %s: %v", opts.File, err)
+	}
+
+	log.AddHook(&writerHook{
+		w:         f,
+		levels:    log.AllLevels,
+		formatter: &log.TextFormatter{FullTimestamp: true, DisableColors: true},
+	})
+	return nil
+}
+
+func levelsUpTo(level log.Level) []log.Level {
+	levels := make([]log.Level, 0)
+	for _, l := range log.AllLevels {
+		if l <= level {
+			levels = append(levels, l)
+		}
+	}
+	return levels
+}
+
+// writerHook writes the entries of the given levels to w
+type writerHook struct {
+	w         io.Writer
+	levels    []log.Level
+	formatter log.Formatter
+}
+
+func (h *writerHook) Levels() []log.Level {
+	return h.levels
+}
+
+func (h *writerHook) Fire(entry *log.Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(b)
+	return err
+}
+
+// RotatingFile is an io.Writer appending to a file, which gets rotated to
+// <file>.1, <file>.2, ... once it grows past a maximum size
+type RotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	f       *os.File
+	size    int64
+}
+
+// NewRotatingFile opens (or creates) file, creating its folder if needed
+func NewRotatingFile(file string, maxSize int64, keep int) (*RotatingFile, error) {
+	r := &RotatingFile{path: file, maxSize: maxSize, keep: keep}
+	if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+// Write appends p to the file, rotating it first if p would make it grow past
+// the maximum size
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the underlying file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+
+	// the oldest file falls off the end
+	for i := r.keep - 1; i > 0; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.keep+1))
+
+	return r.open()
+}
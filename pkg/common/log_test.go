@@ -0,0 +1,45 @@
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-logs")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "logs", "logics.log")
+	f, err := common.NewRotatingFile(file, 10, 2)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.Close())
+
+	for name, expected := range map[string]string{
+		"logics.log":   "fourth\n",
+		"logics.log.1": "third\n",
+		"logics.log.2": "second\n",
+	} {
+		content, err := ioutil.ReadFile(path.Join(dir, "logs", name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+
+	// only the configured number of rotated files is kept
+	_, err = os.Stat(file + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,132 @@
+package common
+
+import "strings"
+
+// Reason classifies the common failures of git and git-lfs
+type Reason uint8
+
+const (
+	// UnknownReason is a failure that could not be classified
+	UnknownReason Reason = iota
+	// NonFastForward means the remote has changes the local repository does
+	// not have yet
+	NonFastForward
+	// AuthFailed means the remote could not be accessed
+	AuthFailed
+	// MissingLFSObject means a large file is not (yet) in the shared folder
+	MissingLFSObject
+	// DiskFull means there is no space left on the device
+	DiskFull
+	// NotARepo means the folder is not a git repository
+	NotARepo
+	// DirtyTree means local changes prevent the operation
+	DirtyTree
+	// MergeConflict means local and remote changes touch the same files
+	MergeConflict
+	// Interrupted means the command was interrupted by the user
+	Interrupted
+	// TimedOut means the command did not complete in time
+	TimedOut
+	// FilePermission means the file system (e.g. macOS privacy protection)
+	// does not allow to access a folder
+	FilePermission
+)
+
+var reasonNames = map[Reason]string{
+	UnknownReason:    "unknown",
+	NonFastForward:   "non-fast-forward",
+	AuthFailed:       "auth-failed",
+	MissingLFSObject: "missing-lfs-object",
+	DiskFull:         "disk-full",
+	NotARepo:         "not-a-repo",
+	DirtyTree:        "dirty-tree",
+	MergeConflict:    "merge-conflict",
+	Interrupted:      "interrupted",
+	TimedOut:         "timed-out",
+	FilePermission:   "file-permission",
+}
+
+func (r Reason) String() string {
+	return reasonNames[r]
+}
+
+// MarshalText renders the Reason by name, e.g. in JSON output
+func (r Reason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// patterns recognize a Reason from the output of git and git-lfs. They are
+// matched in order, so more specific patterns come first
+var patterns = []struct {
+	reason  Reason
+	markers []string
+}{
+	{DiskFull, []string{"No space left on device", "disk quota exceeded"}},
+	{NotARepo, []string{"not a git repository"}},
+	{MissingLFSObject, []string{"Object does not exist", "Unable to find source for object", "smudge filter lfs failed", "missing object"}},
+	{FilePermission, []string{"Operation not permitted"}},
+	{AuthFailed, []string{"Authentication failed", "Permission denied", "could not read Username"}},
+	{NonFastForward, []string{"non-fast-forward", "fetch first", "Updates were rejected"}},
+	{MergeConflict, []string{"CONFLICT (", "Automatic merge failed", "fix conflicts"}},
+	{DirtyTree, []string{"would be overwritten by", "Please commit your changes or stash them", "untracked working tree files"}},
+}
+
+// classify returns the Reason of a failure given the command output
+func classify(output string) Reason {
+	for _, p := range patterns {
+		for _, m := range p.markers {
+			if strings.Contains(output, m) {
+				return p.reason
+			}
+		}
+	}
+	return UnknownReason
+}
+
+type hint struct {
+	explanation string
+	suggestion  string
+}
+
+var hints = map[Reason]hint{
+	NonFastForward: {
+		"someone uploaded changes to the project that you do not have yet",
+		"run `logics download` first, then `logics upload` again",
+	},
+	AuthFailed: {
+		"the shared folder (or a file within it) could not be accessed",
+		"check that the shared folder is synced and that you have write access to it, then run `logics config get sharedfolder` to verify its location",
+	},
+	MissingLFSObject: {
+		"an audio file referenced by the project is not in the shared folder (yet)",
+		"wait for the shared folder to finish syncing and retry. If it persists, ask whoever uploaded the change to run `logics upload` again",
+	},
+	DiskFull: {
+		"there is no space left on the disk",
+		"free some space (or move the project with `logics move`) and retry",
+	},
+	NotARepo: {
+		"the project folder is not a logics project (anymore)",
+		"run `logics relink` if the project was moved, or `logics install` to install it again",
+	},
+	DirtyTree: {
+		"you have local changes to files that the remote also changed",
+		"put your local changes aside with `git stash` within the project folder, download, then bring them back with `git stash pop` and upload them",
+	},
+	MergeConflict: {
+		"you and someone else changed the same files",
+		"run `logics status` to see the conflicting files and decide which version to keep",
+	},
+	Interrupted: {
+		"the operation was interrupted before completing",
+		"run `logics status` to check the state of the project, then run the command again",
+	},
+	TimedOut: {
+		"the operation took longer than the --timeout allowed",
+		"run the command again with a longer --timeout (or without it)",
+	},
+	FilePermission: {
+		"the system does not allow logics to access the project or the shared folder",
+		"on macOS, give your terminal (or the app running logics) access to the folders in System Preferences > Security & Privacy > Privacy (Files and Folders, or Full Disk Access), and check the permissions of the folders",
+	},
+}
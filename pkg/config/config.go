@@ -0,0 +1,77 @@
+// config is the package handling the logics configuration file
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SharedRoot is the portable prefix used for remotes living in the shared
+// folder. Each machine maps it onto its own shared folder, so that clones
+// (and their .git/config) can be copied across machines and users
+const SharedRoot = "logics://shared/"
+
+type (
+	// Repo is a project installed on this machine
+	Repo struct {
+		Name     string `yaml:"name"`
+		Location string `yaml:"location"`
+		Remote   string `yaml:"remote,omitempty"`
+		// Ignore overrides the files kept out of this project, on top of
+		// Conf.Ignore
+		Ignore []string `yaml:"ignore,omitempty"`
+		// Only and Exclude remember which local changes get uploaded
+		Only    []string `yaml:"only,omitempty"`
+		Exclude []string `yaml:"exclude,omitempty"`
+		// FetchInclude and FetchExclude tell which large files get
+		// downloaded, as lfs.fetchinclude and lfs.fetchexclude
+		FetchInclude []string `yaml:"fetchinclude,omitempty"`
+		FetchExclude []string `yaml:"fetchexclude,omitempty"`
+	}
+
+	// Conf is the content of the configuration file
+	Conf struct {
+		Version       int    `yaml:"version"`
+		SharedFolder  string `yaml:"sharedfolder"`
+		ProjectFolder string `yaml:"projectfolder"`
+		Repos         []Repo `yaml:"repos,flow"`
+		// Ignore overrides the files kept out of every project: patterns
+		// get ignored on top of the defaults, while !pattern drops a default
+		Ignore []string `yaml:"ignore,omitempty"`
+	}
+)
+
+// FindRepo returns the index of the configured repository with the given name
+func (c *Conf) FindRepo(name string) (int, error) {
+	for i, repo := range c.Repos {
+		if repo.Name == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no project named %s is configured. Run `logics list` to see the configured projects", name)
+}
+
+// RemoteOf returns the path of the bare repository backing a configured
+// project on this machine
+func (c *Conf) RemoteOf(repo Repo) string {
+	if repo.Remote != "" {
+		return c.ResolveRemote(repo.Remote)
+	}
+	return path.Join(c.SharedFolder, repo.Name+".git")
+}
+
+// ResolveRemote maps a portable remote onto the shared folder of this
+// machine. Absolute remotes are returned as they are
+func (c *Conf) ResolveRemote(remote string) string {
+	if !strings.HasPrefix(remote, SharedRoot) {
+		return remote
+	}
+	return path.Join(c.SharedFolder, strings.TrimPrefix(remote, SharedRoot))
+}
+
+// PortableRemote returns the machine independent url of a bare repository
+// in the shared folder
+func PortableRemote(bareRepo string) string {
+	return SharedRoot + path.Base(bareRepo)
+}
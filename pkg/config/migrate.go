@@ -0,0 +1,86 @@
+package config
+
+import "fmt"
+
+// CurrentVersion is the schema version written by this version of logics
+const CurrentVersion = 1
+
+// migration upgrades the raw content of a configuration file from version
+// `from` to version `from+1`
+type migration struct {
+	from  int
+	desc  string
+	apply func(raw map[string]interface{}) error
+}
+
+// migrations are applied in order to configuration files with a version
+// lower than CurrentVersion. Files written before versioning was introduced
+// have version 0
+var migrations = []migration{
+	{0, "persist the portable remote of every project", persistRemotes},
+}
+
+// migrate upgrades raw to CurrentVersion and returns the version it started
+// from
+func migrate(raw map[string]interface{}) (int, error) {
+	version, err := versionOf(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	if version > CurrentVersion {
+		return version, fmt.Errorf("configuration version %d was written by a newer logics (this one supports up to %d). Please upgrade logics", version, CurrentVersion)
+	}
+
+	from := version
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			return from, fmt.Errorf("could not migrate configuration from version %d (%s): %v", m.from, m.desc, err)
+		}
+		version++
+		raw["version"] = version
+	}
+
+	if version != CurrentVersion {
+		return from, fmt.Errorf("no migration path from configuration version %d", version)
+	}
+	return from, nil
+}
+
+func versionOf(raw map[string]interface{}) (int, error) {
+	v, ok := raw["version"]
+	if !ok || v == nil {
+		return 0, nil
+	}
+
+	version, ok := v.(int)
+	if !ok {
+		return 0, fmt.Errorf("invalid configuration version %v", v)
+	}
+	return version, nil
+}
+
+// persistRemotes fills the remote of projects installed before it got
+// persisted. Those were always cloned from <sharedfolder>/<name>.git
+func persistRemotes(raw map[string]interface{}) error {
+	repos, ok := raw["repos"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, r := range repos {
+		repo, ok := r.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("invalid project entry %v", r)
+		}
+
+		if remote, ok := repo["remote"]; ok && remote != "" {
+			continue
+		}
+		repo["remote"] = PortableRemote(fmt.Sprintf("%v.git", repo["name"]))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// Store reads and writes the configuration file. Older configuration files
+// are migrated to CurrentVersion when loaded
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store for the configuration file at path
+func NewStore(path string) *Store {
+	return &Store{path}
+}
+
+// Path of the configuration file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Exists reports whether the configuration file has been written already
+func (s *Store) Exists() bool {
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// Load reads the configuration file. A missing file yields an empty
+// configuration. Files written by older versions of logics are migrated and
+// saved back (keeping a backup of the original)
+func (s *Store) Load() (*Conf, error) {
+	conf := &Conf{Version: CurrentVersion}
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return conf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	from, err := migrate(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(m, conf); err != nil {
+		return nil, err
+	}
+
+	if from != CurrentVersion {
+		log.WithFields(log.Fields{"from": from, "to": CurrentVersion}).Debugln("configuration migrated")
+		if err := s.Save(conf); err != nil {
+			return nil, err
+		}
+	}
+	return conf, nil
+}
+
+// Save atomically writes the configuration file. The previous file, if any,
+// is kept as <path>.bak
+func (s *Store) Save(conf *Conf) error {
+	conf.Version = CurrentVersion
+	m, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(m); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if old, err := ioutil.ReadFile(s.path); err == nil {
+		if err := ioutil.WriteFile(s.path+".bak", old, 0644); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
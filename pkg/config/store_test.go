@@ -0,0 +1,95 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const v0 = `sharedfolder: /Users/pippo/Dropbox/logic
+projectfolder: /Users/pippo/Music/Logic
+repos: [{name: capelli-curti, location: /Users/pippo/Music/Logic/capelli-curti}]
+`
+
+func tmpStore(t *testing.T, content string) (*config.Store, func()) {
+	dir, err := ioutil.TempDir("", "logics-config")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cfgFile := path.Join(dir, ".logics.yml")
+	if content != "" {
+		if !assert.NoError(t, ioutil.WriteFile(cfgFile, []byte(content), 0644)) {
+			t.FailNow()
+		}
+	}
+	return config.NewStore(cfgFile), func() { os.RemoveAll(dir) }
+}
+
+func TestLoadMissing(t *testing.T) {
+	store, cleanup := tmpStore(t, "")
+	defer cleanup()
+
+	conf, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, config.CurrentVersion, conf.Version)
+	assert.False(t, store.Exists())
+}
+
+func TestMigrateUnversioned(t *testing.T) {
+	store, cleanup := tmpStore(t, v0)
+	defer cleanup()
+
+	conf, err := store.Load()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, config.CurrentVersion, conf.Version)
+	assert.Equal(t, "/Users/pippo/Dropbox/logic", conf.SharedFolder)
+	assert.Equal(t, "logics://shared/capelli-curti.git", conf.Repos[0].Remote)
+	assert.Equal(t, "/Users/pippo/Dropbox/logic/capelli-curti.git", conf.RemoteOf(conf.Repos[0]))
+
+	// the migrated configuration is written back, keeping the original
+	bak, err := ioutil.ReadFile(store.Path() + ".bak")
+	assert.NoError(t, err)
+	assert.Equal(t, v0, string(bak))
+
+	reloaded, err := config.NewStore(store.Path()).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, conf, reloaded)
+}
+
+func TestNewerVersion(t *testing.T) {
+	store, cleanup := tmpStore(t, "version: 999\n")
+	defer cleanup()
+
+	_, err := store.Load()
+	assert.Error(t, err)
+}
+
+func TestSave(t *testing.T) {
+	store, cleanup := tmpStore(t, "")
+	defer cleanup()
+
+	conf := &config.Conf{SharedFolder: "/shared"}
+	assert.NoError(t, store.Save(conf))
+	_, err := os.Stat(store.Path() + ".bak")
+	assert.True(t, os.IsNotExist(err))
+
+	conf.ProjectFolder = "/projects"
+	assert.NoError(t, store.Save(conf))
+
+	reloaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "/projects", reloaded.ProjectFolder)
+
+	// no temporary file is left behind
+	files, err := ioutil.ReadDir(path.Dir(store.Path()))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+}
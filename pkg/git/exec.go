@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/progress"
+)
+
+// Exec runs the git installed on this machine
+type Exec struct {
+	client
+	// Context interrupts the running command when done
+	Context context.Context
+	// OnLine, if set, receives the output of the commands as it gets written
+	OnLine func(line string)
+	// Progress, if set, returns how to render the progress of large file
+	// transfers and how often
+	Progress func() (progress.Renderer, time.Duration)
+}
+
+// NewExec creates an Exec interrupting commands when ctx is done
+func NewExec(ctx context.Context) *Exec {
+	e := &Exec{Context: ctx}
+	e.client = client{e}
+	return e
+}
+
+func (e *Exec) run(repo string, args []string) (string, error) {
+	return common.ExecCmdContext(e.Context, common.ExecOptions{}, "git", withRepo(repo, args)...)
+}
+
+func (e *Exec) stream(repo string, args []string) (string, error) {
+	return common.ExecCmdContext(e.Context, e.streaming(), "git", withRepo(repo, args)...)
+}
+
+// streaming returns the options handing the output over to OnLine
+func (e *Exec) streaming() common.ExecOptions {
+	opts := common.ExecOptions{}
+	if e.OnLine != nil {
+		opts.OnLine = func(_ common.Stream, line string) { e.OnLine(line) }
+	}
+	return opts
+}
+
+// transfer runs the command while following the progress git-lfs reports
+// through the GIT_LFS_PROGRESS file
+func (e *Exec) transfer(repo string, args []string) (int64, error) {
+	f, err := ioutil.TempFile("", "logics-lfs-progress")
+	if err != nil {
+		return 0, err
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+
+	var renderer progress.Renderer = progress.NopRenderer{}
+	interval := time.Second
+	if e.Progress != nil {
+		renderer, interval = e.Progress()
+	}
+
+	tracker := progress.NewTracker()
+	ctx, stop := context.WithCancel(context.Background())
+	watched := make(chan struct{})
+	go func() {
+		progress.Watch(ctx, f.Name(), tracker, renderer, interval)
+		close(watched)
+	}()
+
+	opts := e.streaming()
+	opts.Env = []string{"GIT_LFS_PROGRESS=" + f.Name()}
+	_, err = common.ExecCmdContext(e.Context, opts, "git", withRepo(repo, args)...)
+
+	stop()
+	<-watched
+	stats := tracker.Stats()
+	renderer.Done(stats)
+	return stats.Bytes, err
+}
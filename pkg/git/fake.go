@@ -0,0 +1,63 @@
+package git
+
+import "strings"
+
+// Call is a git command recorded by Fake
+type Call struct {
+	Repo string
+	Args []string
+}
+
+// String renders the arguments of the call, e.g. "push origin master"
+func (c Call) String() string {
+	return strings.Join(c.Args, " ")
+}
+
+// Fake records the git commands instead of running them, replying with
+// canned outputs and errors. It is meant for tests
+type Fake struct {
+	client
+	Calls []Call
+	// Outputs and Errors are keyed by the arguments of the command, as
+	// rendered by Call.String
+	Outputs map[string]string
+	Errors  map[string]error
+	// Transferred is the number of bytes every transfer reports
+	Transferred int64
+}
+
+// NewFake creates a Fake replying to every command with an empty output
+func NewFake() *Fake {
+	f := &Fake{
+		Outputs: make(map[string]string),
+		Errors:  make(map[string]error),
+	}
+	f.client = client{f}
+	return f
+}
+
+// Commands returns the arguments of the recorded calls
+func (f *Fake) Commands() []string {
+	cmds := make([]string, len(f.Calls))
+	for i, c := range f.Calls {
+		cmds[i] = c.String()
+	}
+	return cmds
+}
+
+func (f *Fake) run(repo string, args []string) (string, error) {
+	c := Call{repo, args}
+	f.Calls = append(f.Calls, c)
+	return f.Outputs[c.String()], f.Errors[c.String()]
+}
+
+func (f *Fake) stream(repo string, args []string) (string, error) {
+	return f.run(repo, args)
+}
+
+func (f *Fake) transfer(repo string, args []string) (int64, error) {
+	if _, err := f.run(repo, args); err != nil {
+		return 0, err
+	}
+	return f.Transferred, nil
+}
@@ -0,0 +1,129 @@
+// git is the package wrapping the git operations logics performs on projects,
+// so that they can be replaced by a Fake in tests
+package git
+
+import "strings"
+
+// Git is the set of git (and git-lfs) operations logics performs. Operations
+// transferring large files return the number of bytes transferred
+type Git interface {
+	// Clone clones remote into local
+	Clone(remote, local string) (int64, error)
+	// Pull downloads the remote changes of repo
+	Pull(repo string) (int64, error)
+	// Push uploads the local commits of repo
+	Push(repo string) (int64, error)
+	// Checkout resets the working copy of repo to rev
+	Checkout(repo, rev string) (int64, error)
+	// Status returns the uncommitted changes of repo
+	Status(repo string) ([]Change, error)
+	// AddAll stages every change of repo
+	AddAll(repo string) error
+	// Commit commits the staged changes of repo, only the ones to paths if
+	// any is given
+	Commit(repo, msg string, paths ...string) error
+	// Config runs `git config` within repo. An empty repo works on the
+	// configuration of the user (e.g. with --global)
+	Config(repo string, args ...string) (string, error)
+	// LFS runs `git lfs` within repo
+	LFS(repo string, args ...string) (string, error)
+	// Run runs any other git command within repo
+	Run(repo string, args ...string) (string, error)
+}
+
+// Change is an uncommitted change as reported by `git status`
+type Change struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+}
+
+// runner runs git with the given arguments, either for real or not. Commands
+// changing the project stream their output, transfers their progress too
+type runner interface {
+	run(repo string, args []string) (string, error)
+	stream(repo string, args []string) (string, error)
+	transfer(repo string, args []string) (int64, error)
+}
+
+// client implements Git on top of a runner, so that Exec and Fake share the
+// exact same command lines
+type client struct {
+	r runner
+}
+
+func (c client) Clone(remote, local string) (int64, error) {
+	return c.r.transfer("", []string{"clone", remote, local})
+}
+
+func (c client) Pull(repo string) (int64, error) {
+	return c.r.transfer(repo, []string{"pull", "origin", "master"})
+}
+
+func (c client) Push(repo string) (int64, error) {
+	return c.r.transfer(repo, []string{"push", "origin", "master"})
+}
+
+func (c client) Checkout(repo, rev string) (int64, error) {
+	return c.r.transfer(repo, []string{"reset", "--hard", rev})
+}
+
+func (c client) Status(repo string) ([]Change, error) {
+	out, err := c.r.run(repo, []string{"status", "--porcelain", "-z"})
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(out), nil
+}
+
+func (c client) AddAll(repo string) error {
+	_, err := c.r.stream(repo, []string{"add", "-A", "."})
+	return err
+}
+
+func (c client) Commit(repo, msg string, paths ...string) error {
+	args := []string{"commit", "-m", msg}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	_, err := c.r.stream(repo, args)
+	return err
+}
+
+func (c client) Config(repo string, args ...string) (string, error) {
+	return c.r.run(repo, append([]string{"config"}, args...))
+}
+
+func (c client) LFS(repo string, args ...string) (string, error) {
+	return c.r.run(repo, append([]string{"lfs"}, args...))
+}
+
+func (c client) Run(repo string, args ...string) (string, error) {
+	return c.r.run(repo, args)
+}
+
+// parseStatus parses the output of `git status --porcelain -z`
+func parseStatus(out string) []Change {
+	changes := make([]Change, 0)
+	// entries are NUL terminated; renames and copies are followed by an
+	// additional entry with the original path
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		if len(e) < 4 {
+			continue
+		}
+		changes = append(changes, Change{e[:2], e[3:]})
+		if e[0] == 'R' || e[0] == 'C' {
+			i++
+		}
+	}
+	return changes
+}
+
+// withRepo prepends `-C repo` to args, unless repo is empty
+func withRepo(repo string, args []string) []string {
+	if repo == "" {
+		return args
+	}
+	return append([]string{"-C", repo}, args...)
+}
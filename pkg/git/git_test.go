@@ -0,0 +1,43 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	fake := git.NewFake()
+	fake.Outputs["status --porcelain -z"] = " M song.logicx/Alternatives/000/ProjectData\x00" +
+		"R  Audio Files/Vox Take 2.wav\x00Audio Files/Vox#02.wav\x00" +
+		"?? Bounces/mix v1.wav\x00"
+
+	changes, err := fake.Status("/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []git.Change{
+		{" M", "song.logicx/Alternatives/000/ProjectData"},
+		{"R ", "Audio Files/Vox Take 2.wav"},
+		{"??", "Bounces/mix v1.wav"},
+	}, changes)
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	fake := git.NewFake()
+	fake.Errors["push origin master"] = assert.AnError
+	fake.Transferred = 42
+
+	n, err := fake.Pull("/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), n)
+
+	_, err = fake.Push("/repo")
+	assert.Equal(t, assert.AnError, err)
+
+	_, err = fake.Config("", "--global", "user.name")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"pull origin master", "push origin master", "config --global user.name"}, fake.Commands())
+	assert.Equal(t, "/repo", fake.Calls[0].Repo)
+	assert.Equal(t, "", fake.Calls[2].Repo)
+}
@@ -0,0 +1,95 @@
+package logics
+
+import (
+	"bufio"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/audio"
+	"github.com/autholykos/logics/pkg/git"
+)
+
+// lfsPointerVersion starts the pointers git-lfs commits in place of large
+// files
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// AudioFile describes an audio file of a project
+type AudioFile struct {
+	Path string      `json:"path"`
+	Info *audio.Info `json:"info"`
+}
+
+// String renders the file with its description, e.g. "Vox#01.wav: 0:42,
+// stereo, 48 kHz, 24-bit"
+func (a AudioFile) String() string {
+	return a.Path + ": " + a.Info.String()
+}
+
+// AudioInfo reads the headers of an audio file of repo as of rev, an empty
+// rev being the working copy. Files stored by git-lfs can be read only once
+// downloaded
+func (c *Client) AudioInfo(repo, rev, file string) (*audio.Info, error) {
+	if rev == "" {
+		return audio.ReadFile(filepath.Join(repo, filepath.FromSlash(file)))
+	}
+
+	out, err := c.Git.Run(repo, "show", rev+":"+file)
+	if err != nil {
+		return nil, err
+	}
+	if oid := lfsPointerOid(out); oid != "" {
+		return audio.ReadFile(filepath.Join(lfsObjects(repo), oid[0:2], oid[2:4], oid))
+	}
+	return audio.Read(strings.NewReader(out))
+}
+
+// lfsPointerOid returns the object a git-lfs pointer points to, or an empty
+// string if content is not a pointer
+func lfsPointerOid(content string) string {
+	if !strings.HasPrefix(content, lfsPointerVersion) {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if oid := strings.TrimPrefix(scanner.Text(), "oid sha256:"); oid != scanner.Text() && len(oid) == 64 {
+			return oid
+		}
+	}
+	return ""
+}
+
+// changedAudio describes the audio files added or modified among the local
+// changes of repo
+func (c *Client) changedAudio(repo string, changes []git.Change) []AudioFile {
+	var files []AudioFile
+	for _, ch := range expand(repo, changes) {
+		if strings.Contains(ch.Status, "D") || !audio.Supported(ch.Path) {
+			continue
+		}
+		if info, err := c.AudioInfo(repo, "", ch.Path); err == nil {
+			files = append(files, AudioFile{Path: ch.Path, Info: info})
+		}
+	}
+	return files
+}
+
+// committedAudio describes the audio files added or modified by commit
+func (c *Client) committedAudio(repo, commit string) []AudioFile {
+	out, err := c.Git.Run(repo, "show", "--format=", "--name-only", "--diff-filter=AM", commit)
+	if err != nil {
+		return nil
+	}
+
+	var files []AudioFile
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if !audio.Supported(path.Base(file)) {
+			continue
+		}
+		if info, err := c.AudioInfo(repo, commit, file); err == nil {
+			files = append(files, AudioFile{Path: file, Info: info})
+		}
+	}
+	return files
+}
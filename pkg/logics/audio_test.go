@@ -0,0 +1,97 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/internal/harness"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-audio")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	// a take in the working copy and one downloaded by git-lfs
+	oid := "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	objects := path.Join(dir, ".git", "lfs", "objects", oid[0:2], oid[2:4])
+	if !assert.NoError(t, os.MkdirAll(objects, 0755)) || !assert.NoError(t, os.MkdirAll(path.Join(dir, "Audio Files"), 0755)) {
+		t.FailNow()
+	}
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "Audio Files", "Vox#02.wav"), harness.WAV(1, 44100, 16, 44100*42), 0644))
+	assert.NoError(t, ioutil.WriteFile(path.Join(objects, oid), harness.WAV(2, 48000, 24, 48000), 0644))
+
+	c, fake := newFakeClient("")
+	fake.Outputs["show abc:Audio Files/Vox#01.wav"] = "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 288044\n"
+	fake.Outputs["show abc:Bounces/mix.wav"] = string(harness.WAV(2, 44100, 16, 0))
+	fake.Outputs["show def:Audio Files/Vox#01.wav"] = "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid[:63] + "0\nsize 288044\n"
+
+	info, err := c.AudioInfo(dir, "", "Audio Files/Vox#02.wav")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0:42, mono, 44.1 kHz, 16-bit", info.String())
+	}
+	info, err = c.AudioInfo(dir, "abc", "Audio Files/Vox#01.wav")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0:01, stereo, 48 kHz, 24-bit", info.String())
+	}
+	info, err = c.AudioInfo(dir, "abc", "Bounces/mix.wav")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0:00, stereo, 44.1 kHz, 16-bit", info.String())
+	}
+
+	// not downloaded
+	_, err = c.AudioInfo(dir, "def", "Audio Files/Vox#01.wav")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAnnotateAudio(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["show --format= --name-only --diff-filter=AM abc"] = "Audio Files/Vox#01.wav\nnotes.txt\nAudio Files/Vox#02.wav\n"
+	fake.Outputs["show abc:Audio Files/Vox#01.wav"] = string(harness.WAV(1, 48000, 24, 48000*3))
+
+	commits := []logics.Commit{{Hash: "abc"}}
+	c.Annotate("/repo", commits)
+	if assert.Len(t, commits[0].Audio, 1) {
+		assert.Equal(t, "Audio Files/Vox#01.wav: 0:03, mono, 48 kHz, 24-bit", commits[0].Audio[0].String())
+	}
+}
+
+func TestStatusAudio(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-audio")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	if !assert.NoError(t, os.MkdirAll(path.Join(dir, "Takes"), 0755)) {
+		t.FailNow()
+	}
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "Takes", "Vox#01.wav"), harness.WAV(2, 44100, 24, 44100*61), 0644))
+
+	c, fake := newFakeClient("")
+	fake.Outputs["rev-parse --abbrev-ref HEAD"] = "master\n"
+	fake.Outputs["status --porcelain -z"] = "?? Takes/\x00 D Audio Files/Old.wav\x00"
+
+	messages := make([]string, 0)
+	c.OnMessage = func(msg string) { messages = append(messages, msg) }
+
+	res, err := c.Status(config.Repo{Name: "song", Location: dir})
+	if assert.NoError(t, err) && assert.Len(t, res.Audio, 1) {
+		assert.Equal(t, "Takes/Vox#01.wav: 1:01, stereo, 44.1 kHz, 24-bit", res.Audio[0].String())
+	}
+
+	_, err = c.Changes(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Following changes have been detected for " + dir,
+		"?? Takes/",
+		"?? Takes/Vox#01.wav (1:01, stereo, 44.1 kHz, 24-bit)",
+		"D Audio Files/Old.wav",
+	}, messages)
+}
@@ -0,0 +1,49 @@
+// Package logics is the library behind the logics command line: it shares,
+// installs, downloads, uploads and inspects Logic projects hosted on a shared
+// folder. It never prompts nor prints, so that other tools (e.g. a menubar
+// app) can embed it
+package logics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/git"
+)
+
+// Client performs the logics operations through git
+type Client struct {
+	// Git runs the git commands
+	Git git.Git
+	// SharedFolder is the location of the shared folder on this machine
+	SharedFolder string
+	// Ignore overrides the files kept out of every project (see
+	// IgnorePatterns), on top of the overrides of each project
+	Ignore []string
+	// OnMessage, if set, receives the messages meant for the user
+	OnMessage func(msg string)
+}
+
+// New creates a Client working with the shared folder sharedDir
+func New(g git.Git, sharedDir string) *Client {
+	return &Client{Git: g, SharedFolder: sharedDir}
+}
+
+// message notifies the user, if anybody listens
+func (c *Client) message(a ...interface{}) {
+	msg := strings.TrimSpace(fmt.Sprintln(a...))
+	if c.OnMessage == nil || msg == "" {
+		return
+	}
+	c.OnMessage(msg)
+}
+
+// run runs a git command within repo, passing its output on to the user
+func (c *Client) run(repo string, args ...string) error {
+	out, err := c.Git.Run(repo, args...)
+	if err != nil {
+		return err
+	}
+	c.message(out)
+	return nil
+}
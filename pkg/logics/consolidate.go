@@ -0,0 +1,140 @@
+package logics
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// Consolidation is the copy of an audio file stored outside of a project into
+// the media folder of the song using it
+type Consolidation struct {
+	Song string `json:"song"`
+	From string `json:"from"`
+	// To is relative to the project
+	To   string `json:"to"`
+	Size int64  `json:"size"`
+	// Conflict tells that a different file with the same name is in the
+	// way, or gets copied there first. Logic finds moved audio by name, so
+	// it does not get copied
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// consolidationTarget is where the audio file of ref gets copied to, relative
+// to the project
+func consolidationTarget(ref audioRef) string {
+	return path.Join(ref.song.Bundle, "Media", "Audio Files", filepath.Base(ref.file))
+}
+
+// consolidated returns the copy of the external audio file of ref within the
+// project, if any
+func consolidated(repo string, ref audioRef) (string, bool) {
+	target := consolidationTarget(ref)
+	fi, err := os.Stat(filepath.Join(repo, filepath.FromSlash(target)))
+	return target, err == nil && fi.Size() == fileSize(ref.file)
+}
+
+// PlanConsolidation returns the copies needed to bring the audio the songs of
+// a project use within the project, so that collaborators get it too
+func (c *Client) PlanConsolidation(repo config.Repo) ([]Consolidation, error) {
+	songs, err := c.Songs(repo.Location, "")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]Consolidation, 0)
+	// planned maps the targets to the file copied there
+	planned := make(map[string]string)
+	for _, ref := range references(repo.Location, songs) {
+		if !ref.exists || !filepath.IsAbs(ref.file) {
+			continue
+		}
+		if _, ok := consolidated(repo.Location, ref); ok {
+			continue
+		}
+
+		target := consolidationTarget(ref)
+		from, ok := planned[target]
+		if ok && from == ref.file {
+			continue
+		}
+
+		// another external file with the same name is copied there already
+		_, err := os.Stat(filepath.Join(repo.Location, filepath.FromSlash(target)))
+		if !ok {
+			planned[target] = ref.file
+		}
+		plan = append(plan, Consolidation{
+			Song:     ref.song.Name(),
+			From:     ref.file,
+			To:       target,
+			Size:     fileSize(ref.file),
+			Conflict: ok || err == nil,
+		})
+	}
+	return plan, nil
+}
+
+// Consolidate copies the files of plan into the project, leaving the
+// conflicting ones alone. It returns the bytes copied
+func (c *Client) Consolidate(repo config.Repo, plan []Consolidation) (int64, error) {
+	var copied int64
+	for _, p := range plan {
+		if p.Conflict {
+			c.message("WARNING: not copying", p.From+":", p.To, "is in the way")
+			continue
+		}
+
+		target := filepath.Join(repo.Location, filepath.FromSlash(p.To))
+		if err := copyAudio(p.From, target); err != nil {
+			return copied, fmt.Errorf("could not copy %s into %s: %w", p.From, repo.Name, err)
+		}
+		copied += p.Size
+		c.message("copied", p.From, "to", p.To)
+	}
+	return copied, nil
+}
+
+// copyAudio copies src to dst through a temporary file, so that Logic never
+// sees half of it, keeping its modification time
+func copyAudio(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), ".logics-consolidate-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
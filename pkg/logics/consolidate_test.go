@@ -0,0 +1,73 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsolidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-consolidate")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	repo, elsewhere := path.Join(dir, "song"), path.Join(dir, "Music", "Audio Files")
+	media := path.Join(repo, "song.logicx", "Media", "Audio Files")
+	gtr, keys := path.Join(elsewhere, "Gtr#01.wav"), path.Join(elsewhere, "Keys#01.wav")
+	// a different take with the same name
+	otherGtr := path.Join(dir, "Other", "Gtr#01.wav")
+	files := map[string]string{
+		path.Join(repo, "song.logicx", "Alternatives", "000", "MetaData.plist"): metaDataWithAudio(gtr, keys, "Audio Files/Vox#01.wav"),
+		path.Join(repo, "song.logicx", "Alternatives", "001", "MetaData.plist"): metaDataWithAudio(gtr, otherGtr),
+		path.Join(media, "Vox#01.wav"):                                          "vox",
+		path.Join(media, "Keys#01.wav"):                                         "other keys",
+		gtr:                                                                     "guitar",
+		keys:                                                                    "keys",
+		otherGtr:                                                                "other guitar",
+	}
+	for name, content := range files {
+		if !assert.NoError(t, os.MkdirAll(path.Dir(name), 0755)) || !assert.NoError(t, ioutil.WriteFile(name, []byte(content), 0644)) {
+			t.FailNow()
+		}
+	}
+
+	c, fake := newFakeClient("")
+	song := config.Repo{Name: "song", Location: repo}
+	plan, err := c.PlanConsolidation(song)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []logics.Consolidation{
+		{Song: "song", From: gtr, To: "song.logicx/Media/Audio Files/Gtr#01.wav", Size: 6},
+		{Song: "song", From: keys, To: "song.logicx/Media/Audio Files/Keys#01.wav", Size: 4, Conflict: true},
+		{Song: "song", From: otherGtr, To: "song.logicx/Media/Audio Files/Gtr#01.wav", Size: 12, Conflict: true},
+	}, plan)
+
+	copied, err := c.Consolidate(song, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), copied)
+	content, err := ioutil.ReadFile(path.Join(media, "Gtr#01.wav"))
+	assert.NoError(t, err)
+	assert.Equal(t, "guitar", string(content))
+	content, err = ioutil.ReadFile(path.Join(media, "Keys#01.wav"))
+	assert.NoError(t, err)
+	assert.Equal(t, "other keys", string(content))
+
+	// the guitar is in the project now
+	fake.Outputs["ls-files -z --others --exclude-standard"] = "song.logicx/Media/Audio Files/Gtr#01.wav\x00"
+	report, err := c.CheckMedia(song)
+	assert.NoError(t, err)
+	assert.Equal(t, []logics.MediaFile{{Path: keys, Song: "song", Size: 4}, {Path: otherGtr, Song: "song", Size: 12}}, report.External)
+	assert.Empty(t, report.Unused)
+
+	plan, err = c.PlanConsolidation(song)
+	assert.NoError(t, err)
+	assert.Len(t, plan, 2)
+}
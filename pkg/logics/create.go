@@ -0,0 +1,76 @@
+package logics
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// LFSPatterns are the audio files tracked through git-lfs
+var LFSPatterns = []string{"*.wav", "*.aif", "*.aiff", "*.mp3", "*.m4a", "*.caf"}
+
+// Create creates a bare repository for localRepo within the shared folder
+// and uploads the whole project there
+func (c *Client) Create(localRepo string) (config.Repo, error) {
+	name := filepath.Base(localRepo)
+	remoteRepo := path.Join(c.SharedFolder, name+".git")
+	if _, err := os.Stat(remoteRepo); err == nil {
+		return config.Repo{}, fmt.Errorf("%s already exists. Use `logics install %s` instead", remoteRepo, name)
+	}
+
+	if err := c.run("", "init", "--bare", remoteRepo); err != nil {
+		return config.Repo{}, err
+	}
+	if err := c.run("", "init", localRepo); err != nil {
+		return config.Repo{}, err
+	}
+	// regardless of init.defaultBranch, logics works on master
+	for _, repo := range []string{remoteRepo, localRepo} {
+		if err := c.run(repo, "symbolic-ref", "HEAD", "refs/heads/master"); err != nil {
+			return config.Repo{}, err
+		}
+	}
+
+	if err := c.track(localRepo); err != nil {
+		return config.Repo{}, err
+	}
+
+	if err := c.MapSharedRoot(); err != nil {
+		return config.Repo{}, err
+	}
+	remote := config.PortableRemote(remoteRepo)
+	if err := c.run(localRepo, "remote", "add", "origin", remote); err != nil {
+		return config.Repo{}, err
+	}
+	if err := c.ConfigureLFSAgent(localRepo, remote); err != nil {
+		return config.Repo{}, err
+	}
+	if err := c.run(localRepo, "config", "--replace-all", "lfs.standalonetransferagent", "lfs-folder"); err != nil {
+		return config.Repo{}, err
+	}
+
+	if _, err := c.push(localRepo, fmt.Sprintf("new Logic project %s", name), nil); err != nil {
+		return config.Repo{}, err
+	}
+
+	return config.Repo{
+		Name:     name,
+		Location: localRepo,
+		Remote:   remote,
+	}, nil
+}
+
+// track stores the audio files of localRepo through git-lfs
+func (c *Client) track(localRepo string) error {
+	for _, pattern := range LFSPatterns {
+		out, err := c.Git.LFS(localRepo, "track", pattern)
+		if err != nil {
+			return err
+		}
+		c.message(out)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package logics_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	c, fake := newFakeClient("/Users/pippo/Dropbox/logic")
+
+	repo, err := c.Create("/Users/pippo/Music/Logic/capelli-curti")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, config.Repo{
+		Name:     "capelli-curti",
+		Location: "/Users/pippo/Music/Logic/capelli-curti",
+		Remote:   "logics://shared/capelli-curti.git",
+	}, repo)
+
+	assert.Equal(t, []string{
+		"init --bare /Users/pippo/Dropbox/logic/capelli-curti.git",
+		"init /Users/pippo/Music/Logic/capelli-curti",
+		"symbolic-ref HEAD refs/heads/master",
+		"symbolic-ref HEAD refs/heads/master",
+		"lfs track *.wav",
+		"lfs track *.aif",
+		"lfs track *.aiff",
+		"lfs track *.mp3",
+		"lfs track *.m4a",
+		"lfs track *.caf",
+		"config --global --get-regexp ^url\\..*\\.insteadof$",
+		"config --global --add url./Users/pippo/Dropbox/logic/.insteadOf logics://shared/",
+		"remote add origin logics://shared/capelli-curti.git",
+		"config --replace-all lfs.customtransfer.lfs-folder.path logics",
+		"config --replace-all lfs.customtransfer.lfs-folder.args lfs-agent \"logics://shared/capelli-curti.git\"",
+		"config --replace-all lfs.standalonetransferagent lfs-folder",
+		"add -A .",
+		"commit -m new Logic project capelli-curti",
+		"push origin master",
+	}, fake.Commands())
+}
@@ -0,0 +1,127 @@
+package logics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// Incoming are the commits uploaded by the collaborators and not downloaded
+// yet
+type Incoming struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	// Head is the remote commit, as of the fetch
+	Head    string   `json:"head"`
+	Commits []Commit `json:"commits"`
+}
+
+// Authors returns who made the incoming commits
+func (in *Incoming) Authors() []string {
+	authors := make([]string, 0)
+	for _, c := range in.Commits {
+		authors = appendNew(authors, c.Author)
+	}
+	return authors
+}
+
+// Files returns the files changed by the incoming commits
+func (in *Incoming) Files() []string {
+	files := make([]string, 0)
+	for _, c := range in.Commits {
+		for _, f := range c.Files {
+			files = appendNew(files, f)
+		}
+	}
+	return files
+}
+
+func appendNew(list []string, s string) []string {
+	for _, l := range list {
+		if l == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// Incoming fetches repo and returns the commits not downloaded yet, leaving
+// out those already reported up to the remote commit since (if any)
+func (c *Client) Incoming(repo config.Repo, since string) (*Incoming, error) {
+	if err := c.Fetch(repo.Location); err != nil {
+		return nil, err
+	}
+
+	out, err := c.Git.Run(repo.Location, "rev-parse", RemoteBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Incoming{
+		Name:     repo.Name,
+		Location: repo.Location,
+		Head:     strings.TrimSpace(out),
+		Commits:  make([]Commit, 0),
+	}
+	if in.Head == since {
+		return in, nil
+	}
+
+	// commits reachable from HEAD are local ones, uploaded or downloaded
+	args := []string{"log", "--format=%x1e%H%x1f%an%x1f%aI%x1f%s", "--name-only", RemoteBranch, "^HEAD"}
+	if since != "" {
+		args = append(args, "^"+since)
+	}
+	if out, err = c.Git.Run(repo.Location, args...); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(entry), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		commit := Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]}
+		for _, f := range lines[1:] {
+			if f = strings.TrimSpace(f); f != "" {
+				commit.Files = append(commit.Files, f)
+			}
+		}
+		in.Commits = append(in.Commits, commit)
+	}
+	return in, nil
+}
+
+// Poll looks for incoming commits every interval until ctx is done, or just
+// once if interval is zero. onIncoming gets called with the commits not
+// reported yet
+func (c *Client) Poll(ctx context.Context, repos []config.Repo, interval time.Duration, onIncoming func(*Incoming)) {
+	// the remote commit each project was last checked at
+	seen := make(map[string]string)
+	for {
+		for _, repo := range repos {
+			in, err := c.Incoming(repo, seen[repo.Location])
+			if err != nil {
+				c.message("WARNING: could not fetch", repo.Name+":", err.Error())
+				continue
+			}
+			seen[repo.Location] = in.Head
+			if len(in.Commits) > 0 {
+				onIncoming(in)
+			}
+		}
+
+		if interval <= 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
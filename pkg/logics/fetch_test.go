@@ -0,0 +1,41 @@
+package logics_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncoming(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["rev-parse origin/master"] = "def\n"
+	fake.Outputs["log --format=%x1e%H%x1f%an%x1f%aI%x1f%s --name-only origin/master ^HEAD ^abc"] = "" +
+		"\x1edef\x1fPippo\x1f2020-02-20T10:00:00+01:00\x1fadded vocals\n\nsong.logicx/Alternatives/000/ProjectData\nAudio Files/Vox#01.wav\n" +
+		"\x1ecba\x1fPluto\x1f2020-02-19T10:00:00+01:00\x1fnew bass\n\nsong.logicx/Alternatives/000/ProjectData\n"
+
+	in, err := c.Incoming(config.Repo{Name: "song", Location: "/repo"}, "abc")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "fetch -q origin", fake.Commands()[0])
+	assert.Equal(t, "def", in.Head)
+	assert.Equal(t, []logics.Commit{
+		{Hash: "def", Author: "Pippo", Date: "2020-02-20T10:00:00+01:00", Subject: "added vocals", Files: []string{"song.logicx/Alternatives/000/ProjectData", "Audio Files/Vox#01.wav"}},
+		{Hash: "cba", Author: "Pluto", Date: "2020-02-19T10:00:00+01:00", Subject: "new bass", Files: []string{"song.logicx/Alternatives/000/ProjectData"}},
+	}, in.Commits)
+	assert.Equal(t, []string{"Pippo", "Pluto"}, in.Authors())
+	assert.Equal(t, []string{"song.logicx/Alternatives/000/ProjectData", "Audio Files/Vox#01.wav"}, in.Files())
+}
+
+func TestIncomingAlreadyReported(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["rev-parse origin/master"] = "abc\n"
+
+	in, err := c.Incoming(config.Repo{Name: "song", Location: "/repo"}, "abc")
+	assert.NoError(t, err)
+	assert.Empty(t, in.Commits)
+	assert.Equal(t, []string{"fetch -q origin", "rev-parse origin/master"}, fake.Commands())
+}
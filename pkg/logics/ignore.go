@@ -0,0 +1,145 @@
+package logics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// DefaultIgnores are the files kept out of every project: OS junk, Logic
+// autosaves, backups, freeze files, undo data, overview caches and the
+// temporary files written while saving
+var DefaultIgnores = []string{
+	".DS_Store",
+	"._*",
+	"Icon?",
+	"Autosave/",
+	"Project File Backups/",
+	"Freeze Files/",
+	"Undo Data.nosync/",
+	"*.nosync",
+	"*.ovw",
+	"*.sb-*",
+	"*.tmp",
+	"~*",
+}
+
+const (
+	// ignoreFile is where git looks for the files to ignore
+	ignoreFile = ".gitignore"
+	// the managed block is delimited by these lines, so that it can be
+	// updated leaving the rest of the file alone
+	ignoreBegin = "# BEGIN logics: files kept out of the project, override them with `logics config set ignore`"
+	ignoreEnd   = "# END logics"
+)
+
+// IgnoreFor returns the files kept out of repo: the defaults with the
+// overrides of conf and of repo
+func IgnoreFor(conf *config.Conf, repo config.Repo) []string {
+	return IgnorePatterns(conf.Ignore, repo.Ignore)
+}
+
+// IgnorePatterns applies overrides to DefaultIgnores: patterns get added,
+// while !pattern drops a default (or, for a pattern which is not a default,
+// un-ignores it for git)
+func IgnorePatterns(overrides ...[]string) []string {
+	patterns := append([]string{}, DefaultIgnores...)
+	for _, list := range overrides {
+		for _, o := range list {
+			o = strings.TrimSpace(o)
+			if o == "" {
+				continue
+			}
+			if strings.HasPrefix(o, "!") && remove(&patterns, o[1:]) {
+				continue
+			}
+			remove(&patterns, o)
+			patterns = append(patterns, o)
+		}
+	}
+	return patterns
+}
+
+// remove drops p from list, telling whether it was there
+func remove(list *[]string, p string) bool {
+	for i, item := range *list {
+		if item == p {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateIgnore maintains the managed block of the .gitignore of repo
+func (c *Client) UpdateIgnore(repo config.Repo) error {
+	changed, err := WriteIgnore(repo.Location, IgnorePatterns(c.Ignore, repo.Ignore))
+	if err != nil {
+		return fmt.Errorf("could not update the .gitignore of %s: %w", repo.Name, err)
+	}
+	if changed {
+		c.message("updated the files kept out of", repo.Name, "in .gitignore")
+	}
+	return nil
+}
+
+// WriteIgnore writes patterns in the managed block of the .gitignore of repo,
+// leaving the rest of the file alone. It tells whether the file changed
+func WriteIgnore(repo string, patterns []string) (bool, error) {
+	file := filepath.Join(repo, ignoreFile)
+	content, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	block := ignoreBegin + "\n" + strings.Join(patterns, "\n") + "\n" + ignoreEnd + "\n"
+	updated := replaceBlock(string(content), block)
+	if updated == string(content) {
+		return false, nil
+	}
+	return true, ioutil.WriteFile(file, []byte(updated), 0644)
+}
+
+// replaceBlock replaces the managed block within content, or appends it
+func replaceBlock(content, block string) string {
+	begin := strings.Index(content, ignoreBegin)
+	if begin < 0 {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + block
+	}
+
+	end := strings.Index(content[begin:], ignoreEnd)
+	if end < 0 {
+		// a broken block extends to the end of the file
+		return content[:begin] + block
+	}
+	end += begin + len(ignoreEnd)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:begin] + block + content[end:]
+}
+
+// TrackedJunk returns the files of repo which are committed although ignored
+func (c *Client) TrackedJunk(repo string) ([]string, error) {
+	out, err := c.Git.Run(repo, "ls-files", "-z", "--cached", "--ignored", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return strings.FieldsFunc(out, func(r rune) bool { return r == 0 }), nil
+}
+
+// Untrack stops tracking files, leaving them on disk. The next upload removes
+// them from the shared project
+func (c *Client) Untrack(repo string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return c.run(repo, append([]string{"rm", "--cached", "-q", "--"}, files...)...)
+}
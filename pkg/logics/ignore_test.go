@@ -0,0 +1,73 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnorePatterns(t *testing.T) {
+	assert.Equal(t, logics.DefaultIgnores, logics.IgnorePatterns())
+
+	conf := &config.Conf{Ignore: []string{"Bounces/", "!*.ovw"}}
+	repo := config.Repo{Ignore: []string{"!Bounces/", "!Audio Files/Keep.tmp", " "}}
+	patterns := logics.IgnoreFor(conf, repo)
+	assert.NotContains(t, patterns, "*.ovw")
+	assert.NotContains(t, patterns, "Bounces/")
+	assert.Equal(t, "!Audio Files/Keep.tmp", patterns[len(patterns)-1])
+	assert.Len(t, patterns, len(logics.DefaultIgnores))
+}
+
+func TestWriteIgnore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-ignore")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, ".gitignore")
+
+	// a new file
+	changed, err := logics.WriteIgnore(dir, []string{".DS_Store"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	content, _ := ioutil.ReadFile(file)
+	assert.True(t, strings.HasSuffix(string(content), ".DS_Store\n# END logics\n"))
+
+	changed, err = logics.WriteIgnore(dir, []string{".DS_Store"})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// the lines of the user stay where they are
+	user := "Scratch/\n" + string(content) + "*.mid"
+	assert.NoError(t, ioutil.WriteFile(file, []byte(user), 0644))
+	changed, err = logics.WriteIgnore(dir, []string{".DS_Store", "Autosave/"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	content, _ = ioutil.ReadFile(file)
+	lines := strings.Split(string(content), "\n")
+	assert.Equal(t, "Scratch/", lines[0])
+	assert.Equal(t, []string{".DS_Store", "Autosave/", "# END logics", "*.mid"}, lines[2:])
+	assert.Equal(t, 1, strings.Count(string(content), "# BEGIN logics"))
+}
+
+func TestUntrackJunk(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["ls-files -z --cached --ignored --exclude-standard"] = ".DS_Store\x00song.logicx/Alternatives/000/Autosave/ProjectData\x00"
+
+	junk, err := c.TrackedJunk("/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".DS_Store", "song.logicx/Alternatives/000/Autosave/ProjectData"}, junk)
+
+	assert.NoError(t, c.Untrack("/repo", junk))
+	assert.NoError(t, c.Untrack("/repo", nil))
+	assert.Equal(t, []string{
+		"ls-files -z --cached --ignored --exclude-standard",
+		"rm --cached -q -- .DS_Store song.logicx/Alternatives/000/Autosave/ProjectData",
+	}, fake.Commands())
+}
@@ -0,0 +1,79 @@
+package logics
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// Install clones the bare repository remoteRepo within localDir and
+// configures it to use the shared folder, downloading the large files picked
+// by filter
+func (c *Client) Install(remoteRepo, localDir string, filter FetchFilter) (config.Repo, error) {
+	basename := strings.TrimSuffix(filepath.Base(remoteRepo), ".git")
+	localRepo := path.Join(localDir, basename)
+
+	if err := c.MapSharedRoot(); err != nil {
+		return config.Repo{}, err
+	}
+
+	remote := config.PortableRemote(remoteRepo)
+	if _, err := c.Git.Clone(remote, localRepo); err != nil {
+		return config.Repo{}, fmt.Errorf("error in cloning the repo: %w", err)
+	}
+
+	// before checking out, which downloads the large files
+	if !filter.Empty() {
+		if err := c.SetFetchFilter(localRepo, filter); err != nil {
+			return config.Repo{}, err
+		}
+	}
+
+	if err := c.configureLFSFolderstore(localRepo, remote); err != nil {
+		return config.Repo{}, err
+	}
+
+	return config.Repo{
+		Name:         basename,
+		Location:     localRepo,
+		Remote:       remote,
+		FetchInclude: filter.Include,
+		FetchExclude: filter.Exclude,
+	}, nil
+}
+
+func (c *Client) configureLFSFolderstore(localRepo, remoteRepo string) error {
+	if err := c.ConfigureLFSAgent(localRepo, remoteRepo); err != nil {
+		return err
+	}
+	if err := c.run(localRepo, "config", "--replace-all", "lfs.standalonetransferagent", "lfs-folder"); err != nil {
+		return err
+	}
+	// checking out again downloads the large files through the agent
+	if _, err := c.Git.Checkout(localRepo, "master"); err != nil {
+		return err
+	}
+	c.message("lfs-folderstore configured")
+	return nil
+}
+
+// ConfigureLFSAgent makes git-lfs go through `logics lfs-agent`, which
+// resolves the portable remote against the shared folder of this machine
+func (c *Client) ConfigureLFSAgent(localRepo, remoteRepo string) error {
+	if err := c.run(localRepo, "config", "--replace-all", "lfs.customtransfer.lfs-folder.path", "logics"); err != nil {
+		return err
+	}
+	return c.run(localRepo, "config", "--replace-all", "lfs.customtransfer.lfs-folder.args", fmt.Sprintf(`lfs-agent "%s"`, remoteRepo))
+}
+
+// Relocate rewrites the git remote and the lfs transfer agent of a local
+// repository
+func (c *Client) Relocate(localRepo, remoteRepo string) error {
+	if err := c.run(localRepo, "remote", "set-url", "origin", remoteRepo); err != nil {
+		return err
+	}
+	return c.ConfigureLFSAgent(localRepo, remoteRepo)
+}
@@ -0,0 +1,72 @@
+package logics_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstall(t *testing.T) {
+	c, fake := newFakeClient("/Users/pippo/Dropbox/logic")
+	fake.Outputs["config --global --get-regexp ^url\\..*\\.insteadof$"] = "url./old/shared/.insteadof logics://shared/\n"
+
+	repo, err := c.Install("/Users/pippo/Dropbox/logic/capelli-curti.git", "/Users/pippo/Music/Logic", logics.FetchFilter{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, config.Repo{
+		Name:     "capelli-curti",
+		Location: "/Users/pippo/Music/Logic/capelli-curti",
+		Remote:   "logics://shared/capelli-curti.git",
+	}, repo)
+
+	assert.Equal(t, []string{
+		"config --global --get-regexp ^url\\..*\\.insteadof$",
+		"config --global --unset-all url./old/shared/.insteadof",
+		"config --global --add url./Users/pippo/Dropbox/logic/.insteadOf logics://shared/",
+		"clone logics://shared/capelli-curti.git /Users/pippo/Music/Logic/capelli-curti",
+		"config --replace-all lfs.customtransfer.lfs-folder.path logics",
+		"config --replace-all lfs.customtransfer.lfs-folder.args lfs-agent \"logics://shared/capelli-curti.git\"",
+		"config --replace-all lfs.standalonetransferagent lfs-folder",
+		"reset --hard master",
+	}, fake.Commands())
+}
+
+func TestInstallLite(t *testing.T) {
+	c, fake := newFakeClient("/shared")
+
+	repo, err := c.Install("/shared/song.git", "/music", logics.LiteFilter)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, logics.LiteFilter.Include, repo.FetchInclude)
+
+	// the filter must be in place before the large files get downloaded
+	commands := fake.Commands()
+	include := indexOf(commands, "config --replace-all lfs.fetchinclude Bounces/**,**/Bounces/**")
+	assert.True(t, include > indexOf(commands, "clone logics://shared/song.git /music/song"))
+	assert.True(t, include < indexOf(commands, "reset --hard master"))
+	assert.Contains(t, commands, "config --unset-all lfs.fetchexclude")
+}
+
+func indexOf(list []string, item string) int {
+	for i, s := range list {
+		if s == item {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestIsInstalled(t *testing.T) {
+	conf := &config.Conf{
+		SharedFolder: "/shared",
+		Repos:        []config.Repo{{Name: "song", Location: "/music/song", Remote: "logics://shared/song.git"}},
+	}
+
+	assert.True(t, logics.IsInstalled("/shared/song.git", conf))
+	assert.False(t, logics.IsInstalled("/shared/other.git", conf))
+}
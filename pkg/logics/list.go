@@ -0,0 +1,87 @@
+package logics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logicx"
+)
+
+type (
+	// ProjectInfo describes an installed project
+	ProjectInfo struct {
+		Name       string  `json:"name"`
+		Location   string  `json:"location"`
+		Remote     string  `json:"remote"`
+		Exists     bool    `json:"exists"`
+		Branch     string  `json:"branch,omitempty"`
+		LastCommit *Commit `json:"lastcommit,omitempty"`
+		Changes    int     `json:"changes"`
+		Ahead      int     `json:"ahead"`
+		Behind     int     `json:"behind"`
+		DiskUsage  int64   `json:"diskusage"`
+		// Songs are the Logic songs within the project
+		Songs []*logicx.Song `json:"songs,omitempty"`
+	}
+
+	// RemoteInfo describes a project available in the shared folder
+	RemoteInfo struct {
+		Name      string `json:"name"`
+		Path      string `json:"path"`
+		Installed bool   `json:"installed"`
+	}
+)
+
+// Describe collects the state of an installed project, fetching the remote
+// first if asked to. Failures are not fatal: whatever could be collected gets
+// reported
+func (c *Client) Describe(cfg *config.Conf, repo config.Repo, fetch bool) *ProjectInfo {
+	info := &ProjectInfo{
+		Name:     repo.Name,
+		Location: repo.Location,
+		Remote:   cfg.RemoteOf(repo),
+	}
+
+	if _, err := os.Stat(repo.Location); err != nil {
+		return info
+	}
+	info.Exists = true
+
+	if fetch {
+		if err := c.Fetch(repo.Location); err != nil {
+			c.message("WARNING: could not fetch", repo.Name+":", err.Error())
+		}
+	}
+
+	info.Branch, _ = c.Branch(repo.Location)
+	if commits, err := c.History(repo.Location, 1); err == nil && len(commits) > 0 {
+		info.LastCommit = &commits[0]
+	}
+	if changes, err := c.Git.Status(repo.Location); err == nil {
+		info.Changes = len(changes)
+	}
+	info.Ahead, info.Behind, _ = c.AheadBehind(repo.Location)
+	info.DiskUsage = DirSize(repo.Location)
+	info.Songs, _ = c.Songs(repo.Location, "")
+	return info
+}
+
+// Remotes lists the projects available in the shared folder
+func (c *Client) Remotes(cfg *config.Conf) ([]RemoteInfo, error) {
+	repos, err := c.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := make([]RemoteInfo, 0, len(repos))
+	for _, repo := range repos {
+		remotes = append(remotes, RemoteInfo{
+			Name:      strings.TrimSuffix(filepath.Base(repo), ".git"),
+			Path:      repo,
+			Installed: IsInstalled(repo, cfg),
+		})
+	}
+	return remotes, nil
+}
@@ -0,0 +1,143 @@
+package logics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// LockFile is the file within the shared repository of a project telling who
+// is working on it
+const LockFile = "logics.lock"
+
+// ErrLocked is returned when locking or unlocking a project somebody else
+// locked
+var ErrLocked = errors.New("project locked")
+
+// ProjectLock tells the rest of the team that somebody is working on a
+// project, so that they hold off their changes. It is advisory: uploads only
+// warn about it
+type ProjectLock struct {
+	Owner string    `json:"owner"`
+	Host  string    `json:"host"`
+	Since time.Time `json:"since"`
+}
+
+func (l *ProjectLock) String() string {
+	return fmt.Sprintf("%s on %s since %s", l.Owner, l.Host, l.Since.Local().Format("2006-01-02 15:04"))
+}
+
+// heldBy tells whether owner locked the project from this machine
+func (l *ProjectLock) heldBy(owner, host string) bool {
+	return l.Owner == owner && l.Host == host
+}
+
+// LockOf returns the lock of a project, nil when it is not locked
+func (c *Client) LockOf(repo config.Repo) (*ProjectLock, error) {
+	b, err := ioutil.ReadFile(c.lockFile(repo))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := &ProjectLock{}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, fmt.Errorf("corrupted lock of %s: %w", repo.Name, err)
+	}
+	return l, nil
+}
+
+// Lock locks a project on behalf of owner (the current user when empty). A
+// project locked by owner from this machine stays locked as it is
+func (c *Client) Lock(repo config.Repo, owner string) (*ProjectLock, error) {
+	owner, host := identify(owner)
+	l := &ProjectLock{Owner: owner, Host: host, Since: time.Now().UTC().Truncate(time.Second)}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file gets created only if nobody holds the lock
+	f, err := os.OpenFile(c.lockFile(repo), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		held, err := c.LockOf(repo)
+		if err != nil {
+			return nil, err
+		}
+		if held != nil && held.heldBy(owner, host) {
+			return held, nil
+		}
+		return nil, fmt.Errorf("%w by %s", ErrLocked, held)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not lock %s: %w", repo.Name, err)
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("could not lock %s: %w", repo.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	c.message(repo.Name, "locked by", l)
+	return l, nil
+}
+
+// Unlock releases the lock owner (the current user when empty) holds on a
+// project. force releases somebody else's lock too
+func (c *Client) Unlock(repo config.Repo, owner string, force bool) error {
+	held, err := c.LockOf(repo)
+	if err != nil || held == nil {
+		return err
+	}
+	if owner, host := identify(owner); !force && !held.heldBy(owner, host) {
+		return fmt.Errorf("%w by %s", ErrLocked, held)
+	}
+
+	if err := os.Remove(c.lockFile(repo)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not unlock %s: %w", repo.Name, err)
+	}
+	c.message(repo.Name, "unlocked")
+	return nil
+}
+
+// CheckLock returns ErrLocked when somebody else than owner (the current user
+// when empty) locked a project
+func (c *Client) CheckLock(repo config.Repo, owner string) error {
+	held, err := c.LockOf(repo)
+	if err != nil || held == nil {
+		return err
+	}
+	if owner, host := identify(owner); !held.heldBy(owner, host) {
+		return fmt.Errorf("%w by %s", ErrLocked, held)
+	}
+	return nil
+}
+
+// lockFile returns the lock file of repo, within its shared repository
+func (c *Client) lockFile(repo config.Repo) string {
+	conf := &config.Conf{SharedFolder: c.SharedFolder}
+	return path.Join(conf.RemoteOf(repo), LockFile)
+}
+
+// identify returns owner, or the current user when empty, and this machine
+func identify(owner string) (string, string) {
+	if owner == "" {
+		if u, err := user.Current(); err == nil {
+			owner = u.Username
+		}
+	}
+	host, _ := os.Hostname()
+	return owner, host
+}
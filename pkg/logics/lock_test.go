@@ -0,0 +1,86 @@
+package logics_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	shared, err := ioutil.TempDir("", "logics-lock")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(shared)
+	if !assert.NoError(t, os.Mkdir(path.Join(shared, "song.git"), 0755)) {
+		t.FailNow()
+	}
+
+	c, _ := newFakeClient(shared)
+	repo := config.Repo{Name: "song", Remote: "logics://shared/song.git"}
+
+	l, err := c.LockOf(repo)
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	l, err = c.Lock(repo, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", l.Owner)
+	_, err = os.Stat(path.Join(shared, "song.git", logics.LockFile))
+	assert.NoError(t, err)
+
+	held, err := c.LockOf(repo)
+	assert.NoError(t, err)
+	assert.Equal(t, l, held)
+
+	_, err = c.Lock(repo, "bob")
+	assert.True(t, errors.Is(err, logics.ErrLocked))
+	assert.True(t, errors.Is(c.Unlock(repo, "bob", false), logics.ErrLocked))
+	assert.NoError(t, c.CheckLock(repo, "alice"))
+	assert.True(t, errors.Is(c.CheckLock(repo, "bob"), logics.ErrLocked))
+
+	assert.NoError(t, c.Unlock(repo, "alice", false))
+	l, err = c.LockOf(repo)
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	// unlocking twice is fine, forcing takes over anybody's lock
+	assert.NoError(t, c.Unlock(repo, "alice", false))
+	_, err = c.Lock(repo, "bob")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Unlock(repo, "alice", true))
+}
+
+func TestUploadLocked(t *testing.T) {
+	shared, err := ioutil.TempDir("", "logics-lock")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(shared)
+	if !assert.NoError(t, os.Mkdir(path.Join(shared, "song.git"), 0755)) {
+		t.FailNow()
+	}
+
+	c, fake := newFakeClient(shared)
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+	messages := make([]string, 0)
+	c.OnMessage = func(msg string) { messages = append(messages, msg) }
+
+	repo := config.Repo{Name: "song", Location: shared, Remote: "logics://shared/song.git"}
+	l, err := c.Lock(repo, "alice")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the lock is advisory
+	_, err = c.Upload(repo, "new vocals")
+	assert.NoError(t, err)
+	assert.Contains(t, messages, "WARNING: project locked by "+l.String())
+	assert.Contains(t, fake.Commands(), "commit -m new vocals")
+}
@@ -0,0 +1,206 @@
+package logics
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logicx"
+)
+
+// mediaFolders are the folders Logic records and imports audio into, both
+// within bundles (song.logicx/Media/Audio Files) and project folders
+var mediaFolders = []string{"Audio Files", "Media"}
+
+// MediaFile is an audio file found by CheckMedia
+type MediaFile struct {
+	// Path is relative to the project, or absolute when outside of it
+	Path string `json:"path"`
+	// Song is the song referencing the file, if any
+	Song string `json:"song,omitempty"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// MediaReport lists the audio of a project collaborators will miss or which
+// takes room for nothing
+type MediaReport struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	// External audio is used by the songs but lives outside the project,
+	// so collaborators get "file not found"
+	External []MediaFile `json:"external"`
+	// Missing audio is used by the songs but cannot be found at all
+	Missing []MediaFile `json:"missing"`
+	// Untracked audio is within the project but was never uploaded
+	Untracked []MediaFile `json:"untracked"`
+	// Unused audio is within the project but not used by any song, taking
+	// room in the shared folder
+	Unused []MediaFile `json:"unused"`
+}
+
+// Problems counts the files reported
+func (r *MediaReport) Problems() int {
+	return len(r.External) + len(r.Missing) + len(r.Untracked) + len(r.Unused)
+}
+
+// CheckMedia cross-references the audio files used by the songs of a project
+// with the ones in its media folders and the ones committed
+func (c *Client) CheckMedia(repo config.Repo) (*MediaReport, error) {
+	songs, err := c.Songs(repo.Location, "")
+	if err != nil {
+		return nil, err
+	}
+	tracked, err := c.mediaFiles(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := c.mediaFiles(repo.Location, "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MediaReport{
+		Name:      repo.Name,
+		Location:  repo.Location,
+		External:  make([]MediaFile, 0),
+		Missing:   make([]MediaFile, 0),
+		Untracked: make([]MediaFile, 0),
+		Unused:    make([]MediaFile, 0),
+	}
+
+	used := make(map[string]bool)
+	for _, ref := range references(repo.Location, songs) {
+		switch {
+		case !ref.exists:
+			report.Missing = append(report.Missing, MediaFile{Path: ref.file, Song: ref.song.Name()})
+		case filepath.IsAbs(ref.file):
+			// collaborators get the copy within the project
+			if inProject, ok := consolidated(repo.Location, ref); ok {
+				used[inProject] = true
+				continue
+			}
+			report.External = append(report.External, MediaFile{Path: ref.file, Song: ref.song.Name(), Size: fileSize(ref.file)})
+		default:
+			used[ref.file] = true
+		}
+	}
+
+	for _, file := range untracked {
+		report.Untracked = append(report.Untracked, MediaFile{Path: file, Size: fileSize(filepath.Join(repo.Location, file))})
+	}
+
+	// without songs to tell, every file would look unused
+	if len(songs) > 0 {
+		for _, file := range append(tracked, untracked...) {
+			if !used[file] {
+				report.Unused = append(report.Unused, MediaFile{Path: file, Size: fileSize(filepath.Join(repo.Location, file))})
+			}
+		}
+		sort.Slice(report.Unused, func(i, j int) bool { return report.Unused[i].Path < report.Unused[j].Path })
+	}
+	return report, nil
+}
+
+// mediaFiles lists the audio files within the media folders of repo known to
+// git ls-files with args
+func (c *Client) mediaFiles(repo string, args ...string) ([]string, error) {
+	out, err := c.Git.Run(repo, append([]string{"ls-files", "-z"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, file := range strings.FieldsFunc(out, func(r rune) bool { return r == 0 }) {
+		if isAudio(file) && isMedia(file) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// isMedia tells whether file is within a media folder
+func isMedia(file string) bool {
+	for _, folder := range mediaFolders {
+		if inFolder(file, folder) {
+			return true
+		}
+	}
+	return false
+}
+
+// audioRef is an audio file used by a song
+type audioRef struct {
+	song *logicx.Song
+	// file is relative to the project, or absolute when outside of it
+	file   string
+	exists bool
+}
+
+// references resolves the audio files used by songs
+func references(repo string, songs []*logicx.Song) []audioRef {
+	refs := make([]audioRef, 0)
+	for _, s := range songs {
+		for _, f := range usedAudio(s) {
+			file, ok := resolveAudio(repo, s.Bundle, f)
+			refs = append(refs, audioRef{song: s, file: file, exists: ok})
+		}
+	}
+	return refs
+}
+
+// usedAudio returns the audio files used by any alternative of s
+func usedAudio(s *logicx.Song) []string {
+	seen := make(map[string]bool)
+	files := make([]string, 0)
+	for _, alt := range s.Alternatives {
+		for _, f := range alt.AudioFiles {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// resolveAudio finds the audio file ref used by bundle: absolute or relative
+// to the media folder of the bundle, to the bundle or to the project folder
+// containing it. It returns the file relative to repo, or absolute when
+// outside of it, and whether it exists
+func resolveAudio(repo, bundle, ref string) (string, bool) {
+	var candidates []string
+	if filepath.IsAbs(ref) {
+		candidates = []string{filepath.Clean(ref)}
+	} else {
+		for _, dir := range []string{path.Join(bundle, "Media"), bundle, path.Dir(bundle)} {
+			candidates = append(candidates, filepath.Join(repo, filepath.FromSlash(dir), filepath.FromSlash(ref)))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return relativeTo(repo, candidate), true
+		}
+	}
+	return relativeTo(repo, candidates[len(candidates)-1]), false
+}
+
+// relativeTo returns file relative to repo, or as is when outside of it
+func relativeTo(repo, file string) string {
+	rel, err := filepath.Rel(repo, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// fileSize returns the size of file, 0 if it cannot be read
+func fileSize(file string) int64 {
+	if fi, err := os.Stat(file); err == nil {
+		return fi.Size()
+	}
+	return 0
+}
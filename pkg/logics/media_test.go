@@ -0,0 +1,87 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+// metaDataWithAudio is the metadata of an alternative using files
+func metaDataWithAudio(files ...string) string {
+	list := ""
+	for _, f := range files {
+		list += "<string>" + f + "</string>"
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>BeatsPerMinute</key><integer>120</integer>
+	<key>AudioFiles</key><array>` + list + `</array>
+</dict>
+</plist>`
+}
+
+func TestCheckMedia(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-media")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	repo, elsewhere := path.Join(dir, "song"), path.Join(dir, "Music", "Audio Files")
+	external := path.Join(elsewhere, "Gtr#01.wav")
+	files := map[string]string{
+		path.Join(repo, "song.logicx", "Alternatives", "000", "MetaData.plist"): metaDataWithAudio("Audio Files/Vox#01.wav", external, "Audio Files/Gone.wav"),
+		path.Join(repo, "song.logicx", "Alternatives", "001", "MetaData.plist"): metaDataWithAudio("Audio Files/Vox#01.wav", "Audio Files/Scratch.wav"),
+		path.Join(repo, "song.logicx", "Media", "Audio Files", "Vox#01.wav"):    "vox",
+		path.Join(repo, "song.logicx", "Media", "Audio Files", "Old.wav"):       "old take",
+		path.Join(repo, "Audio Files", "Scratch.wav"):                           "scratch",
+		path.Join(repo, "Audio Files", "Idea.wav"):                              "idea",
+		external: "guitar",
+	}
+	for name, content := range files {
+		if !assert.NoError(t, os.MkdirAll(path.Dir(name), 0755)) || !assert.NoError(t, ioutil.WriteFile(name, []byte(content), 0644)) {
+			t.FailNow()
+		}
+	}
+
+	c, fake := newFakeClient("")
+	fake.Outputs["ls-files -z"] = "song.logicx/Alternatives/000/MetaData.plist\x00song.logicx/Media/Audio Files/Vox#01.wav\x00song.logicx/Media/Audio Files/Old.wav\x00Bounces/mix.wav\x00"
+	fake.Outputs["ls-files -z --others --exclude-standard"] = "Audio Files/Scratch.wav\x00Audio Files/Idea.wav\x00notes.txt\x00"
+
+	report, err := c.CheckMedia(config.Repo{Name: "song", Location: repo})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []logics.MediaFile{{Path: external, Song: "song", Size: 6}}, report.External)
+	assert.Equal(t, []logics.MediaFile{{Path: "Audio Files/Gone.wav", Song: "song"}}, report.Missing)
+	assert.Equal(t, []logics.MediaFile{
+		{Path: "Audio Files/Scratch.wav", Size: 7},
+		{Path: "Audio Files/Idea.wav", Size: 4},
+	}, report.Untracked)
+	assert.Equal(t, []logics.MediaFile{
+		{Path: "Audio Files/Idea.wav", Size: 4},
+		{Path: "song.logicx/Media/Audio Files/Old.wav", Size: 8},
+	}, report.Unused)
+	assert.Equal(t, 6, report.Problems())
+}
+
+func TestCheckMediaWithoutSongs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-media")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	c, fake := newFakeClient("")
+	fake.Outputs["ls-files -z"] = "Audio Files/Vox#01.wav\x00"
+
+	report, err := c.CheckMedia(config.Repo{Name: "song", Location: dir})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Problems())
+}
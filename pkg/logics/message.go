@@ -0,0 +1,213 @@
+package logics
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/autholykos/logics/pkg/git"
+)
+
+// maxNamedFiles is how many files a commit message names for each kind of
+// change
+const maxNamedFiles = 2
+
+// kind classifies the files of a project
+type kind uint8
+
+const (
+	recording kind = iota
+	bounce
+	project
+	other
+)
+
+var kindNames = map[kind]string{recording: "recording", bounce: "bounce", project: "project", other: "other"}
+
+func (k kind) String() string {
+	return kindNames[k]
+}
+
+// Kind tells what a file of a project is: project, recording, bounce or other
+func Kind(file string) string {
+	return classify(file).String()
+}
+
+// classify tells what a file of a project is: bounces live in the Bounces
+// folder, every other audio file is a recording and anything else within a
+// .logicx bundle belongs to the project itself
+func classify(file string) kind {
+	switch {
+	case isAudio(file) && inFolder(file, "Bounces"):
+		return bounce
+	case isAudio(file):
+		return recording
+	case strings.Contains(file, ".logicx/") || strings.HasSuffix(file, ".logicx"):
+		return project
+	default:
+		return other
+	}
+}
+
+// isAudio tells whether file is one of the audio formats tracked by LFS
+func isAudio(file string) bool {
+	ext := strings.ToLower(path.Ext(file))
+	for _, p := range LFSPatterns {
+		if "*"+ext == p {
+			return true
+		}
+	}
+	return false
+}
+
+// inFolder tells whether any folder file is within is called name
+func inFolder(file, name string) bool {
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if strings.EqualFold(dir, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// files of the same kind, by what happened to them
+type changedFiles struct {
+	added, replaced, removed []string
+}
+
+// CommitMessage describes the local changes of repo, e.g. "Added 3
+// recordings (Vox_Take4.wav, Vox_Take5.wav…), replaced bounce Bass.wav,
+// changed tempo 92 → 94 BPM"
+func (c *Client) CommitMessage(repo string, changes []git.Change) string {
+	return commitMessage(expand(repo, changes), c.MusicalChanges(repo, "HEAD", ""))
+}
+
+// commitMessage describes changes, given what changed in the songs
+func commitMessage(changes []git.Change, music []string) string {
+	byKind := make(map[kind]*changedFiles)
+	for _, k := range []kind{recording, bounce, project, other} {
+		byKind[k] = &changedFiles{}
+	}
+	for _, ch := range changes {
+		files := byKind[classify(ch.Path)]
+		name := path.Base(ch.Path)
+		switch {
+		case strings.Contains(ch.Status, "D"):
+			files.removed = append(files.removed, name)
+		case ch.Status == "??" || strings.HasPrefix(ch.Status, "A"):
+			files.added = append(files.added, name)
+		default:
+			files.replaced = append(files.replaced, name)
+		}
+	}
+
+	var phrases []string
+	for _, k := range []kind{recording, bounce} {
+		files := byKind[k]
+		phrases = appendFiles(phrases, "added", k.String(), files.added)
+		phrases = appendFiles(phrases, "replaced", k.String(), files.replaced)
+		phrases = appendFiles(phrases, "removed", k.String(), files.removed)
+	}
+
+	described := false
+	for _, m := range music {
+		// the audio files are described already
+		if strings.Contains(m, "audio file(s)") {
+			continue
+		}
+		phrases = append(phrases, musicalPhrase(m))
+		described = true
+	}
+
+	if p := byKind[project]; !described && len(p.added)+len(p.replaced)+len(p.removed) > 0 {
+		phrases = append(phrases, "edited the arrangement")
+	}
+
+	o := byKind[other]
+	if n := len(o.added) + len(o.replaced) + len(o.removed); n > 0 {
+		phrases = append(phrases, fmt.Sprintf("updated %s", count(n, "other file")))
+	}
+
+	if len(phrases) == 0 {
+		return "Updated project"
+	}
+	return capitalize(strings.Join(phrases, ", "))
+}
+
+// appendFiles appends e.g. "added 3 recordings (a.wav, b.wav…)" to phrases
+func appendFiles(phrases []string, verb, noun string, names []string) []string {
+	switch len(names) {
+	case 0:
+		return phrases
+	case 1:
+		return append(phrases, fmt.Sprintf("%s %s %s", verb, noun, names[0]))
+	}
+
+	listed := names
+	ellipsis := ""
+	if len(names) > maxNamedFiles {
+		listed, ellipsis = names[:maxNamedFiles], "…"
+	}
+	return append(phrases, fmt.Sprintf("%s %s (%s%s)", verb, count(len(names), noun), strings.Join(listed, ", "), ellipsis))
+}
+
+// musicalPhrase turns a musical change into part of a sentence, e.g. "tempo
+// 92 → 94 BPM" into "changed tempo 92 → 94 BPM"
+func musicalPhrase(change string) string {
+	// changes of songs and alternatives carry their name as prefix
+	prefix, what := "", change
+	if i := strings.LastIndex(change, ": "); i >= 0 {
+		prefix, what = change[:i+2], change[i+2:]
+	}
+	for _, property := range []string{"tempo ", "key ", "time signature ", "sample rate ", "tracks "} {
+		if strings.HasPrefix(what, property) {
+			return "changed " + prefix + what
+		}
+	}
+	return change
+}
+
+// count renders n things, e.g. "1 recording" or "3 recordings"
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Expand replaces the new folders git reports as a whole (e.g. a new Audio
+// Files folder) with the files within them
+func Expand(repo string, changes []git.Change) []git.Change {
+	return expand(repo, changes)
+}
+
+// expand replaces the new folders git reports as a whole (e.g. a new Audio
+// Files folder) with the files within them
+func expand(repo string, changes []git.Change) []git.Change {
+	expanded := make([]git.Change, 0, len(changes))
+	for _, ch := range changes {
+		if !strings.HasSuffix(ch.Path, "/") {
+			expanded = append(expanded, ch)
+			continue
+		}
+		_ = filepath.Walk(filepath.Join(repo, ch.Path), func(p string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return nil
+			}
+			if rel, err := filepath.Rel(repo, p); err == nil {
+				expanded = append(expanded, git.Change{Status: ch.Status, Path: filepath.ToSlash(rel)})
+			}
+			return nil
+		})
+	}
+	return expanded
+}
@@ -0,0 +1,71 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitMessage(t *testing.T) {
+	c, _ := newFakeClient("")
+
+	tests := []struct {
+		name    string
+		changes []git.Change
+		want    string
+	}{
+		{"nothing", nil, "Updated project"},
+		{"recording", []git.Change{
+			{Status: "??", Path: "Audio Files/Vox_Take4.wav"},
+		}, "Added recording Vox_Take4.wav"},
+		{"recordings", []git.Change{
+			{Status: "??", Path: "Audio Files/Vox_Take4.wav"},
+			{Status: "??", Path: "Audio Files/Vox_Take5.wav"},
+			{Status: "A ", Path: "Audio Files/Vox_Take6.wav"},
+			{Status: " D", Path: "Audio Files/Scratch.aif"},
+		}, "Added 3 recordings (Vox_Take4.wav, Vox_Take5.wav…), removed recording Scratch.aif"},
+		{"bounce and project", []git.Change{
+			{Status: " M", Path: "Bounces/Bass.wav"},
+			{Status: " M", Path: "song.logicx/Alternatives/000/ProjectData"},
+			{Status: "??", Path: "notes.txt"},
+		}, "Replaced bounce Bass.wav, edited the arrangement, updated 1 other file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.CommitMessage("/repo", tt.changes))
+		})
+	}
+}
+
+func TestCommitMessageMusicalChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-message")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	alt := path.Join(dir, "song.logicx", "Alternatives", "000")
+	takes := path.Join(dir, "Takes")
+	if !assert.NoError(t, os.MkdirAll(alt, 0755)) || !assert.NoError(t, os.MkdirAll(takes, 0755)) {
+		t.FailNow()
+	}
+	assert.NoError(t, ioutil.WriteFile(path.Join(alt, "MetaData.plist"), []byte(metaData(94)), 0644))
+	assert.NoError(t, ioutil.WriteFile(path.Join(takes, "Vox_Take4.wav"), nil, 0644))
+
+	c, fake := newFakeClient("")
+	fake.Outputs["ls-tree -z --name-only HEAD:"] = "song.logicx\x00"
+	fake.Outputs["ls-tree -z --name-only HEAD:song.logicx/Alternatives"] = "000\x00"
+	fake.Outputs["show HEAD:song.logicx/Alternatives/000/MetaData.plist"] = metaData(92)
+
+	// git reports new folders as a whole
+	msg := c.CommitMessage(dir, []git.Change{
+		{Status: "??", Path: "Takes/"},
+		{Status: " M", Path: "song.logicx/Alternatives/000/MetaData.plist"},
+	})
+	assert.Equal(t, "Added recording Vox_Take4.wav, changed tempo 92 → 94 BPM", msg)
+}
@@ -0,0 +1,127 @@
+package logics
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RemoteBranch is the branch every project is synced with
+const RemoteBranch = "origin/master"
+
+// Commit describes a commit in the project history
+type Commit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+	// Files are the files changed, when asked for
+	Files []string `json:"files,omitempty"`
+	// Music describes what changed in the songs, when asked for
+	Music []string `json:"music,omitempty"`
+	// Audio describes the audio files added or modified, when asked for
+	Audio []AudioFile `json:"audio,omitempty"`
+}
+
+// Branch returns the branch checked out in repo
+func (c *Client) Branch(repo string) (string, error) {
+	out, err := c.Git.Run(repo, "rev-parse", "--abbrev-ref", "HEAD")
+	return strings.TrimSpace(out), err
+}
+
+// Head returns the commit checked out in repo, or an empty string for a
+// repository without commits
+func (c *Client) Head(repo string) string {
+	out, err := c.Git.Run(repo, "rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// AheadBehind counts the local commits not yet uploaded and the remote
+// commits not yet downloaded, as of the last time the remote was fetched
+func (c *Client) AheadBehind(repo string) (int, int, error) {
+	out, err := c.Git.Run(repo, "rev-list", "--left-right", "--count", "HEAD..."+RemoteBranch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected output from git rev-list: %s", out)
+	}
+
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	return ahead, behind, err
+}
+
+// Fetch refreshes the remote state of repo without touching its files
+func (c *Client) Fetch(repo string) error {
+	return c.run(repo, "fetch", "-q", "origin")
+}
+
+// countCommits returns the number of commits in the range from..to
+func (c *Client) countCommits(repo, from, to string) int {
+	if from == "" || to == "" || from == to {
+		return 0
+	}
+
+	out, err := c.Git.Run(repo, "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(out))
+	return n
+}
+
+// History returns the latest n commits of repo
+func (c *Client) History(repo string, n int) ([]Commit, error) {
+	out, err := c.Git.Run(repo, "log", fmt.Sprintf("-n%d", n), "--format=%H%x1f%an%x1f%aI%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]})
+	}
+	return commits, nil
+}
+
+// Annotate describes what changed in the songs and which audio files were
+// recorded or replaced with each of the commits
+func (c *Client) Annotate(repo string, commits []Commit) {
+	for i := range commits {
+		commits[i].Music = c.MusicalChanges(repo, commits[i].Hash+"^", commits[i].Hash)
+		commits[i].Audio = c.committedAudio(repo, commits[i].Hash)
+	}
+}
+
+// lfsObjects is the folder git-lfs stores the downloaded objects in
+func lfsObjects(repo string) string {
+	return path.Join(repo, ".git", "lfs", "objects")
+}
+
+// DirSize returns the size in bytes of the files within dir
+func DirSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,39 @@
+package logics_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeClient returns a Client running its commands on a git.Fake
+func newFakeClient(sharedDir string) (*logics.Client, *git.Fake) {
+	fake := git.NewFake()
+	return logics.New(fake, sharedDir), fake
+}
+
+func TestAheadBehind(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["rev-list --left-right --count HEAD...origin/master"] = "2\t5\n"
+
+	ahead, behind, err := c.AheadBehind("/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 5, behind)
+	assert.Equal(t, "/repo", fake.Calls[0].Repo)
+}
+
+func TestHistory(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["log -n2 --format=%H%x1f%an%x1f%aI%x1f%s"] = "abc\x1fPippo\x1f2020-02-20T10:00:00+01:00\x1fadded vocals\n" +
+		"def\x1fPluto\x1f2020-02-19T10:00:00+01:00\x1ffirst draft\n"
+
+	commits, err := c.History("/repo", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []logics.Commit{
+		{Hash: "abc", Author: "Pippo", Date: "2020-02-20T10:00:00+01:00", Subject: "added vocals"},
+		{Hash: "def", Author: "Pluto", Date: "2020-02-19T10:00:00+01:00", Subject: "first draft"},
+	}, commits)
+}
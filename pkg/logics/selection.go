@@ -0,0 +1,122 @@
+package logics
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+)
+
+// ErrNothingSelected is returned when uploading a project whose changes are
+// all left out by its selection
+var ErrNothingSelected = errors.New("none of the changes is selected for upload")
+
+// Selection picks the local changes to upload. Patterns match the path or
+// the name of a file (`Audio Files/*.wav`, `Vox*`), a folder matches
+// everything within it and @project, @recordings, @bounces and @other match
+// the files of that kind
+type Selection struct {
+	Only    []string `json:"only,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// SelectionOf returns the selection remembered for repo
+func SelectionOf(repo config.Repo) Selection {
+	return Selection{Only: repo.Only, Exclude: repo.Exclude}
+}
+
+// Empty tells whether the selection picks every change
+func (s Selection) Empty() bool {
+	return len(s.Only) == 0 && len(s.Exclude) == 0
+}
+
+// Includes tells whether file is selected
+func (s Selection) Includes(file string) bool {
+	if len(s.Only) > 0 && !matchAny(s.Only, file) {
+		return false
+	}
+	return !matchAny(s.Exclude, file)
+}
+
+// Apply returns the selected changes. New folders get expanded first, so that
+// their files can be picked one by one
+func (s Selection) Apply(repo string, changes []git.Change) []git.Change {
+	if s.Empty() {
+		return changes
+	}
+
+	selected := make([]git.Change, 0, len(changes))
+	for _, ch := range expand(repo, changes) {
+		if s.Includes(ch.Path) {
+			selected = append(selected, ch)
+		}
+	}
+	return selected
+}
+
+func matchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if match(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// match tells whether file matches the pattern p of a Selection
+func match(p, file string) bool {
+	if strings.HasPrefix(p, "@") {
+		return strings.TrimSuffix(p[1:], "s") == Kind(file)
+	}
+
+	p = strings.TrimSuffix(p, "/")
+	if ok, _ := path.Match(p, file); ok {
+		return true
+	}
+	if ok, _ := path.Match(p, path.Base(file)); ok {
+		return true
+	}
+	// the files within a folder
+	for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if ok, _ := path.Match(p, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Selected returns the local changes of repo its selection picks
+func (c *Client) Selected(repo config.Repo) ([]git.Change, error) {
+	changes, err := c.Git.Status(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+	return SelectionOf(repo).Apply(repo.Location, changes), nil
+}
+
+// applySelection narrows changes down to the ones selected for repo, telling
+// which ones are left out. It returns nil paths when everything is selected
+func (c *Client) applySelection(repo config.Repo, changes []git.Change) ([]git.Change, []string, error) {
+	sel := SelectionOf(repo)
+	if sel.Empty() {
+		return changes, nil, nil
+	}
+
+	selected := sel.Apply(repo.Location, changes)
+	if len(selected) == 0 {
+		return nil, nil, ErrNothingSelected
+	}
+
+	paths := make([]string, len(selected))
+	for i, ch := range selected {
+		paths[i] = ch.Path
+	}
+	for _, ch := range expand(repo.Location, changes) {
+		if !sel.Includes(ch.Path) {
+			c.message("leaving out", ch.Path)
+		}
+	}
+	return selected, paths, nil
+}
@@ -0,0 +1,111 @@
+package logics_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		sel  logics.Selection
+		in   []string
+		out  []string
+	}{
+		{"everything", logics.Selection{},
+			[]string{"song.logicx/Alternatives/000/ProjectData", "Audio Files/Vox#01.wav"}, nil},
+		{"folder", logics.Selection{Exclude: []string{"Scratch/"}},
+			[]string{"Audio Files/Vox#01.wav", "Scratch.wav"}, []string{"Scratch/idea.wav", "Scratch/ideas/2.wav"}},
+		{"name", logics.Selection{Exclude: []string{"*_scratch.wav"}},
+			[]string{"Audio Files/Vox.wav"}, []string{"Audio Files/Vox_scratch.wav"}},
+		{"path", logics.Selection{Only: []string{"Audio Files/Vox*"}},
+			[]string{"Audio Files/Vox#01.wav"}, []string{"Audio Files/Bass#01.wav", "Vox/1.wav"}},
+		{"kinds", logics.Selection{Only: []string{"@project", "@bounces"}},
+			[]string{"song.logicx/Alternatives/000/ProjectData", "Bounces/mix.wav"}, []string{"Audio Files/Vox#01.wav", "notes.txt"}},
+		{"kinds and patterns", logics.Selection{Only: []string{"@recordings"}, Exclude: []string{"Scratch"}},
+			[]string{"Audio Files/Vox#01.wav"}, []string{"Scratch/idea.wav", "Bounces/mix.wav"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, f := range tt.in {
+				assert.True(t, tt.sel.Includes(f), f)
+			}
+			for _, f := range tt.out {
+				assert.False(t, tt.sel.Includes(f), f)
+			}
+		})
+	}
+}
+
+func TestUploadSelection(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["status --porcelain -z"] = " M song.logicx/Alternatives/000/ProjectData\x00?? Audio Files/Vox#01.wav\x00?? Audio Files/Vox_scratch.wav\x00"
+
+	messages := make([]string, 0)
+	c.OnMessage = func(msg string) { messages = append(messages, msg) }
+
+	repo := config.Repo{Name: "song", Location: "/repo", Exclude: []string{"*_scratch.wav"}}
+	_, err := c.Upload(repo, "")
+	assert.NoError(t, err)
+	assert.Contains(t, fake.Commands(), "add -A -- song.logicx/Alternatives/000/ProjectData Audio Files/Vox#01.wav")
+	assert.Contains(t, fake.Commands(), "commit -m Added recording Vox#01.wav, edited the arrangement -- song.logicx/Alternatives/000/ProjectData Audio Files/Vox#01.wav")
+	assert.NotContains(t, fake.Commands(), "add -A .")
+	assert.Contains(t, messages, "leaving out Audio Files/Vox_scratch.wav")
+
+	repo.Exclude, repo.Only = nil, []string{"@bounces"}
+	_, err = c.Upload(repo, "")
+	assert.Equal(t, logics.ErrNothingSelected, err)
+}
+
+func TestUploadSelectionLeavesOutStaged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "logics-selection")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	shared, repo := path.Join(dir, "song.git"), path.Join(dir, "song")
+	gitRun(t, dir, "init", "-q", "--bare", shared)
+	gitRun(t, dir, "init", "-q", repo)
+	gitRun(t, repo, "symbolic-ref", "HEAD", "refs/heads/master")
+	gitRun(t, repo, "config", "user.name", "pippo")
+	gitRun(t, repo, "config", "user.email", "pippo@example.com")
+	gitRun(t, repo, "remote", "add", "origin", shared)
+
+	for _, f := range []string{"Vox#01.wav", "Vox_scratch.wav"} {
+		if !assert.NoError(t, ioutil.WriteFile(path.Join(repo, f), []byte(f), 0644)) {
+			t.FailNow()
+		}
+	}
+	// e.g. by an upload which failed
+	gitRun(t, repo, "add", "Vox_scratch.wav")
+
+	c := logics.New(git.NewExec(context.Background()), dir)
+	_, err = c.Upload(config.Repo{Name: "song", Location: repo, Exclude: []string{"*_scratch.wav"}}, "new vocals")
+	assert.NoError(t, err)
+	// the .gitignore written by the upload goes along
+	assert.Equal(t, ".gitignore\nVox#01.wav\n", gitRun(t, repo, "show", "--format=", "--name-only", "HEAD"))
+	assert.Equal(t, "A  Vox_scratch.wav\n", gitRun(t, repo, "status", "--porcelain"))
+}
+
+// gitRun runs git within dir, failing the test on error
+func gitRun(t *testing.T, dir string, args ...string) string {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if !assert.NoError(t, err, string(out)) {
+		t.FailNow()
+	}
+	return string(out)
+}
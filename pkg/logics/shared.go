@@ -0,0 +1,112 @@
+package logics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// MapSharedRoot makes git on this machine resolve the portable remotes
+// against the shared folder, replacing any previous mapping
+func (c *Client) MapSharedRoot() error {
+	out, _ := c.Git.Config("", "--global", "--get-regexp", `^url\..*\.insteadof$`)
+	for _, line := range strings.Split(out, "\n") {
+		// the shared folder may contain spaces, the value never does
+		i := strings.LastIndex(line, " ")
+		if i < 0 || line[i+1:] != config.SharedRoot {
+			continue
+		}
+		if _, err := c.Git.Config("", "--global", "--unset-all", line[:i]); err != nil {
+			return err
+		}
+	}
+
+	key := fmt.Sprintf("url.%s/.insteadOf", strings.TrimSuffix(c.SharedFolder, "/"))
+	if _, err := c.Git.Config("", "--global", "--add", key, config.SharedRoot); err != nil {
+		return fmt.Errorf("could not map %s onto %s: %w", config.SharedRoot, c.SharedFolder, err)
+	}
+	return nil
+}
+
+// Projects returns the bare repositories found in the shared folder
+func (c *Client) Projects() ([]string, error) {
+	files, err := ioutil.ReadDir(c.SharedFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]string, 0)
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+
+		// candidate bare repository found
+		repo := path.Join(c.SharedFolder, f.Name())
+		if !c.IsBare(repo) {
+			continue
+		}
+
+		// NOTE: projects are in the form [/path/to/project.git]
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// Available returns the bare repositories of the projects not installed yet
+func (c *Client) Available(conf *config.Conf) ([]string, error) {
+	repos, err := c.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]string, 0)
+	for _, repo := range repos {
+		if IsInstalled(repo, conf) {
+			continue
+		}
+		projects = append(projects, repo)
+	}
+	return projects, nil
+}
+
+// FindProject returns the bare repository of the project called name, as long
+// as it is not installed yet
+func (c *Client) FindProject(conf *config.Conf, name string) (string, error) {
+	repo := path.Join(c.SharedFolder, strings.TrimSuffix(name, ".git")+".git")
+	if !c.IsBare(repo) {
+		return "", fmt.Errorf("no project %s found in %s", name, c.SharedFolder)
+	}
+	if IsInstalled(repo, conf) {
+		return "", fmt.Errorf("project %s is already installed", name)
+	}
+	return repo, nil
+}
+
+// IsBare tells whether repo is a bare repository
+func (c *Client) IsBare(repo string) bool {
+	out, err := c.Git.Run(repo, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(out) == "true"
+}
+
+// IsInstalled tells whether the project of the bare repository repo is
+// installed on this machine
+func IsInstalled(repo string, conf *config.Conf) bool {
+	if conf.Repos == nil {
+		return false
+	}
+	for _, r := range conf.Repos {
+		if filepath.Base(conf.RemoteOf(r)) == filepath.Base(repo) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package logics
+
+import (
+	"strings"
+
+	"github.com/autholykos/logics/pkg/logicx"
+)
+
+// gitFS reads the files of a repository as of a revision
+type gitFS struct {
+	c    *Client
+	repo string
+	rev  string
+}
+
+func (g gitFS) ReadFile(name string) ([]byte, error) {
+	out, err := g.c.Git.Run(g.repo, "show", g.rev+":"+name)
+	return []byte(out), err
+}
+
+func (g gitFS) ReadDir(name string) ([]string, error) {
+	out, err := g.c.Git.Run(g.repo, "ls-tree", "-z", "--name-only", g.rev+":"+name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.FieldsFunc(out, func(r rune) bool { return r == 0 }), nil
+}
+
+// songFS returns how to read the songs of repo as of rev, an empty rev being
+// the working copy
+func (c *Client) songFS(repo, rev string) logicx.FS {
+	if rev == "" {
+		return logicx.Dir(repo)
+	}
+	return gitFS{c, repo, rev}
+}
+
+// Songs returns the Logic songs of repo as of rev, an empty rev being the
+// working copy
+func (c *Client) Songs(repo, rev string) ([]*logicx.Song, error) {
+	return logicx.Songs(c.songFS(repo, rev), "")
+}
+
+// MusicalChanges describes what changed in the songs of repo from the
+// revision from to the revision to (an empty to being the working copy)
+func (c *Client) MusicalChanges(repo, from, to string) []string {
+	after, err := c.Songs(repo, to)
+	if err != nil || len(after) == 0 {
+		return nil
+	}
+	// the first commit has nothing before
+	before, _ := c.Songs(repo, from)
+
+	previous := make(map[string]*logicx.Song, len(before))
+	for _, s := range before {
+		previous[s.Bundle] = s
+	}
+
+	var changes []string
+	for _, s := range after {
+		prefix := ""
+		if len(after) > 1 {
+			prefix = s.Name() + ": "
+		}
+		for _, change := range logicx.Diff(previous[s.Bundle], s) {
+			changes = append(changes, prefix+change)
+		}
+	}
+	return changes
+}
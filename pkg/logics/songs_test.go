@@ -0,0 +1,45 @@
+package logics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func metaData(bpm int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>BeatsPerMinute</key><integer>%d</integer>
+	<key>NumberOfTracks</key><integer>8</integer>
+</dict>
+</plist>`, bpm)
+}
+
+func TestMusicalChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-songs")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	alt := path.Join(dir, "song.logicx", "Alternatives", "000")
+	if !assert.NoError(t, os.MkdirAll(alt, 0755)) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, ioutil.WriteFile(path.Join(alt, "MetaData.plist"), []byte(metaData(124)), 0644)) {
+		t.FailNow()
+	}
+
+	c, fake := newFakeClient("")
+	fake.Outputs["ls-tree -z --name-only HEAD:"] = "Audio Files\x00song.logicx\x00"
+	fake.Outputs["ls-tree -z --name-only HEAD:song.logicx/Alternatives"] = "000\x00"
+	fake.Outputs["show HEAD:song.logicx/Alternatives/000/MetaData.plist"] = metaData(120)
+
+	assert.Equal(t, []string{"tempo 120 → 124 BPM"}, c.MusicalChanges(dir, "HEAD", ""))
+	assert.Equal(t, []string{"new song song"}, c.MusicalChanges(dir, "missing", ""))
+}
@@ -0,0 +1,91 @@
+package logics
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+)
+
+// FetchFilter tells which large files get downloaded: the ones matching
+// Include (all of them when empty) but not Exclude. Patterns follow
+// lfs.fetchinclude and lfs.fetchexclude, e.g. `Audio Files/Vox*` or
+// `**/Bounces/**`. The files left out stay in the project as small pointers
+type FetchFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// LiteFilter downloads the bounces only, besides the project files which are
+// never left out
+var LiteFilter = FetchFilter{Include: []string{"Bounces/**", "**/Bounces/**"}}
+
+// FetchFilterOf returns the filter configured for repo
+func FetchFilterOf(repo config.Repo) FetchFilter {
+	return FetchFilter{Include: repo.FetchInclude, Exclude: repo.FetchExclude}
+}
+
+// Empty tells whether the filter downloads everything
+func (f FetchFilter) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// SetFetchFilter makes git-lfs download the large files of repo picked by f
+// from now on
+func (c *Client) SetFetchFilter(repo string, f FetchFilter) error {
+	settings := []struct {
+		key      string
+		patterns []string
+	}{{"lfs.fetchinclude", f.Include}, {"lfs.fetchexclude", f.Exclude}}
+
+	for _, s := range settings {
+		if len(s.patterns) > 0 {
+			if err := c.run(repo, "config", "--replace-all", s.key, strings.Join(s.patterns, ",")); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.run(repo, "config", "--unset-all", s.key); err != nil && !isUnsetKey(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isUnsetKey tells whether err is git config failing to unset a key which is
+// not set
+func isUnsetKey(err error) bool {
+	var e *common.ExecErr
+	return errors.As(err, &e) && e.ExitCode == 5
+}
+
+// FetchMedia downloads the large files of a project matching patterns,
+// regardless of the filter of the project. Without patterns it downloads every
+// file the filter of repo picks, including the ones a narrower filter left
+// out before (e.g. everything once the filter is cleared)
+func (c *Client) FetchMedia(repo config.Repo, patterns []string) (*Transfer, error) {
+	res := &Transfer{
+		Name:     repo.Name,
+		Location: repo.Location,
+		From:     c.Head(repo.Location),
+	}
+	res.To = res.From
+	before := DirSize(lfsObjects(repo.Location))
+
+	// explicit (even empty) patterns override lfs.fetchinclude and
+	// lfs.fetchexclude
+	filter := FetchFilter{Include: patterns}
+	if len(patterns) == 0 {
+		filter = FetchFilterOf(repo)
+	}
+	args := []string{"pull", "--include", strings.Join(filter.Include, ","), "--exclude", strings.Join(filter.Exclude, ",")}
+	out, err := c.Git.LFS(repo.Location, args...)
+	if err != nil {
+		return nil, err
+	}
+	c.message(out)
+
+	res.Bytes = DirSize(lfsObjects(repo.Location)) - before
+	return res, nil
+}
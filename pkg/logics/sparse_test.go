@@ -0,0 +1,75 @@
+package logics_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFetchFilter(t *testing.T) {
+	c, fake := newFakeClient("")
+	assert.NoError(t, c.SetFetchFilter("/song", logics.LiteFilter))
+	assert.Equal(t, []string{
+		"config --replace-all lfs.fetchinclude Bounces/**,**/Bounces/**",
+		"config --unset-all lfs.fetchexclude",
+	}, fake.Commands())
+
+	// unsetting a key which is not set is fine
+	c, fake = newFakeClient("")
+	fake.Errors["config --unset-all lfs.fetchinclude"] = &common.ExecErr{ExitCode: 5}
+	fake.Errors["config --unset-all lfs.fetchexclude"] = &common.ExecErr{ExitCode: 5}
+	assert.NoError(t, c.SetFetchFilter("/song", logics.FetchFilter{}))
+
+	c, fake = newFakeClient("")
+	fake.Errors["config --unset-all lfs.fetchinclude"] = &common.ExecErr{ExitCode: 4}
+	assert.Error(t, c.SetFetchFilter("/song", logics.FetchFilter{}))
+}
+
+func TestFetchFilterOf(t *testing.T) {
+	assert.True(t, logics.FetchFilterOf(config.Repo{}).Empty())
+	f := logics.FetchFilterOf(config.Repo{FetchExclude: []string{"Scratch/**"}})
+	assert.False(t, f.Empty())
+	assert.Equal(t, []string{"Scratch/**"}, f.Exclude)
+}
+
+func TestFetchMedia(t *testing.T) {
+	c, fake := newFakeClient("")
+	repo := config.Repo{Name: "song", Location: "/song"}
+
+	res, err := c.FetchMedia(repo, []string{"Audio Files/Vox*", "**/Drums/**"})
+	assert.NoError(t, err)
+	assert.Equal(t, "song", res.Name)
+	assert.Contains(t, fake.Commands(), "lfs pull --include Audio Files/Vox*,**/Drums/** --exclude ")
+
+	fake.Errors["lfs pull --include  --exclude "] = assert.AnError
+	_, err = c.FetchMedia(repo, nil)
+	assert.Equal(t, assert.AnError, err)
+}
+
+func TestFetchMediaAfterChangingFilter(t *testing.T) {
+	c, fake := newFakeClient("")
+	repo := config.Repo{Name: "song", Location: "/song"}
+
+	// going lite, then back to everything
+	for _, f := range []logics.FetchFilter{logics.LiteFilter, {}} {
+		assert.NoError(t, c.SetFetchFilter(repo.Location, f))
+		repo.FetchInclude, repo.FetchExclude = f.Include, f.Exclude
+		_, err := c.FetchMedia(repo, nil)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"config --replace-all lfs.fetchinclude Bounces/**,**/Bounces/**",
+		"config --unset-all lfs.fetchexclude",
+		"rev-parse HEAD",
+		"lfs pull --include Bounces/**,**/Bounces/** --exclude ",
+		"config --unset-all lfs.fetchinclude",
+		"config --unset-all lfs.fetchexclude",
+		"rev-parse HEAD",
+		// the files left out by the lite filter
+		"lfs pull --include  --exclude ",
+	}, fake.Commands())
+}
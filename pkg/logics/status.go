@@ -0,0 +1,61 @@
+package logics
+
+import (
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logicx"
+)
+
+// ProjectStatus describes the state of a local project
+type ProjectStatus struct {
+	Name     string       `json:"name"`
+	Location string       `json:"location"`
+	Branch   string       `json:"branch"`
+	Ahead    int          `json:"ahead"`
+	Behind   int          `json:"behind"`
+	Changes  []git.Change `json:"changes"`
+	// Songs are the Logic songs within the project and Music what changed
+	// in them since the last commit
+	Songs []*logicx.Song `json:"songs,omitempty"`
+	Music []string       `json:"music,omitempty"`
+	// Audio describes the audio files added or modified locally
+	Audio []AudioFile `json:"audio,omitempty"`
+	// Junk are the files committed although they should be kept out
+	Junk []string `json:"junk,omitempty"`
+	// Lock tells who is working on the project, if anybody
+	Lock *ProjectLock `json:"lock,omitempty"`
+}
+
+// Status returns the local changes of a project and how it compares to the
+// remote, as of the last time the remote was fetched
+func (c *Client) Status(repo config.Repo) (*ProjectStatus, error) {
+	branch, err := c.Branch(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := c.Git.Status(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	// a project without any upload yet has nothing to compare against
+	ahead, behind, _ := c.AheadBehind(repo.Location)
+
+	res := &ProjectStatus{
+		Name:     repo.Name,
+		Location: repo.Location,
+		Branch:   branch,
+		Ahead:    ahead,
+		Behind:   behind,
+		Changes:  changes,
+	}
+	res.Songs, _ = c.Songs(repo.Location, "")
+	res.Junk, _ = c.TrackedJunk(repo.Location)
+	res.Lock, _ = c.LockOf(repo)
+	if len(changes) > 0 {
+		res.Music = c.MusicalChanges(repo.Location, "HEAD", "")
+		res.Audio = c.changedAudio(repo.Location, changes)
+	}
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package logics
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+)
+
+// ErrNoChanges is returned when uploading a project without local changes
+var ErrNoChanges = errors.New("no changes detected: nothing to do!")
+
+// Transfer is the outcome of a download or an upload
+type Transfer struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Commits  int    `json:"commits"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// Download pulls the remote changes of a project
+func (c *Client) Download(repo config.Repo) (*Transfer, error) {
+	res := &Transfer{
+		Name:     repo.Name,
+		Location: repo.Location,
+		From:     c.Head(repo.Location),
+	}
+	lfsBefore := DirSize(lfsObjects(repo.Location))
+
+	transferred, err := c.Git.Pull(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	res.To = c.Head(repo.Location)
+	res.Commits = c.countCommits(repo.Location, res.From, res.To)
+	res.Bytes = transferred
+	if res.Bytes == 0 {
+		// git-lfs without progress reporting
+		res.Bytes = DirSize(lfsObjects(repo.Location)) - lfsBefore
+	}
+	return res, nil
+}
+
+// Upload commits the local changes of a project picked by its selection (all
+// of them by default) and pushes them. An empty msg gets the commit message
+// generated from the changes
+func (c *Client) Upload(repo config.Repo, msg string) (*Transfer, error) {
+	// projects shared before logics kept junk out need the .gitignore too,
+	// but junk is no reason not to upload
+	if err := c.UpdateIgnore(repo); err != nil {
+		c.message("WARNING:", err)
+	}
+	if err := c.CheckLock(repo, ""); err != nil {
+		c.message("WARNING:", err)
+	}
+
+	changes, err := c.Changes(repo.Location)
+	if err != nil {
+		return nil, err
+	}
+	changes, paths, err := c.applySelection(repo, changes)
+	if err != nil {
+		return nil, err
+	}
+	if msg == "" {
+		msg = c.CommitMessage(repo.Location, changes)
+	}
+
+	res := &Transfer{
+		Name:     repo.Name,
+		Location: repo.Location,
+		From:     c.Head(repo.Location),
+	}
+
+	transferred, err := c.push(repo.Location, msg, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	res.To = c.Head(repo.Location)
+	res.Commits = c.countCommits(repo.Location, res.From, res.To)
+	res.Bytes = transferred
+	if res.Bytes == 0 {
+		// git-lfs without progress reporting: everything that changed
+		// locally is what travels to the shared folder
+		for _, ch := range changes {
+			if fi, err := os.Stat(path.Join(repo.Location, ch.Path)); err == nil && fi.Mode().IsRegular() {
+				res.Bytes += fi.Size()
+			}
+		}
+	}
+	return res, nil
+}
+
+// Changes returns the local changes of repo, or ErrNoChanges
+func (c *Client) Changes(repo string) ([]git.Change, error) {
+	changes, err := c.Git.Status(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	c.message("Following changes have been detected for", repo)
+	audio := c.changedAudio(repo, changes)
+	for _, ch := range changes {
+		described := false
+		for _, a := range audio {
+			switch {
+			case a.Path == ch.Path:
+				c.message(ch.Status, ch.Path, "("+a.Info.String()+")")
+				described = true
+			case strings.HasSuffix(ch.Path, "/") && strings.HasPrefix(a.Path, ch.Path):
+				// the audio within new folders
+				if !described {
+					c.message(ch.Status, ch.Path)
+					described = true
+				}
+				c.message(ch.Status, a.Path, "("+a.Info.String()+")")
+			}
+		}
+		if !described {
+			c.message(ch.Status, ch.Path)
+		}
+	}
+	return changes, nil
+}
+
+// push commits the changes to paths (every change when nil) and uploads
+// them, returning the bytes of large files transferred
+func (c *Client) push(repo, msg string, paths []string) (int64, error) {
+	if err := c.commit(repo, msg, paths); err != nil {
+		return 0, err
+	}
+	return c.Git.Push(repo)
+}
+
+// commit commits the changes to paths, every change when nil. Changes staged
+// already to other paths (e.g. by an upload which failed) stay out
+func (c *Client) commit(repo, msg string, paths []string) error {
+	if err := c.stage(repo, paths); err != nil {
+		return err
+	}
+	return c.Git.Commit(repo, msg, paths...)
+}
+
+// stage stages the changes to paths, every change when nil
+func (c *Client) stage(repo string, paths []string) error {
+	if paths == nil {
+		return c.Git.AddAll(repo)
+	}
+	return c.run(repo, append([]string{"add", "-A", "--"}, paths...)...)
+}
@@ -0,0 +1,93 @@
+package logics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-upload")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	c, fake := newFakeClient("")
+	fake.Outputs["status --porcelain -z"] = " M song.logicx/Alternatives/000/ProjectData\x00?? Audio Files/Vox#01.wav\x00"
+	fake.Transferred = 1024
+
+	messages := make([]string, 0)
+	c.OnMessage = func(msg string) { messages = append(messages, msg) }
+
+	res, err := c.Upload(config.Repo{Name: "song", Location: dir}, "new vocals")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []string{
+		"status --porcelain -z",
+		"rev-parse HEAD",
+		"add -A .",
+		"commit -m new vocals",
+		"push origin master",
+		"rev-parse HEAD",
+	}, fake.Commands())
+	assert.Equal(t, int64(1024), res.Bytes)
+	assert.Equal(t, []string{
+		"updated the files kept out of song in .gitignore",
+		"Following changes have been detected for " + dir,
+		"M song.logicx/Alternatives/000/ProjectData",
+		"?? Audio Files/Vox#01.wav",
+	}, messages)
+}
+
+func TestUploadGeneratedMessage(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+
+	_, err := c.Upload(config.Repo{Name: "song", Location: "/repo"}, "")
+	assert.NoError(t, err)
+	assert.Contains(t, fake.Commands(), "commit -m Added recording Vox#01.wav")
+}
+
+func TestUploadNothingToDo(t *testing.T) {
+	c, fake := newFakeClient("")
+
+	_, err := c.Upload(config.Repo{Name: "song", Location: "/repo"}, "new vocals")
+	assert.Equal(t, logics.ErrNoChanges, err)
+	assert.Equal(t, []string{"status --porcelain -z"}, fake.Commands())
+}
+
+func TestDownload(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Outputs["rev-parse HEAD"] = "abc\n"
+	fake.Outputs["rev-list --count abc..abc"] = "0\n"
+	fake.Transferred = 2048
+
+	res, err := c.Download(config.Repo{Name: "song", Location: "/repo"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []string{"rev-parse HEAD", "pull origin master", "rev-parse HEAD"}, fake.Commands())
+	assert.Equal(t, &logics.Transfer{
+		Name:     "song",
+		Location: "/repo",
+		From:     "abc",
+		To:       "abc",
+		Bytes:    2048,
+	}, res)
+}
+
+func TestDownloadFailure(t *testing.T) {
+	c, fake := newFakeClient("")
+	fake.Errors["pull origin master"] = assert.AnError
+
+	_, err := c.Download(config.Repo{Name: "song", Location: "/repo"})
+	assert.Equal(t, assert.AnError, err)
+}
@@ -0,0 +1,237 @@
+package logics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchOptions configure Watch
+type WatchOptions struct {
+	// Settle is how long a project must be left alone before its changes get
+	// committed. Logic saves in bursts of writes
+	Settle time.Duration
+	// PushInterval is how often the commits get uploaded. Zero means never
+	PushInterval time.Duration
+}
+
+// maxWaits is how many times Watch waits opts.Settle for Logic to finish
+// writing a temporary file before committing anyway
+const maxWaits = 10
+
+// watched is the state of a project being watched
+type watched struct {
+	repo config.Repo
+	// changed is when the last change was noticed, zero if none is pending
+	changed time.Time
+	// waiting is since when Logic is waited for to finish writing, zero if
+	// it is not
+	waiting time.Time
+}
+
+// Watch commits the changes of the projects as soon as Logic is done saving
+// them, uploading the commits every opts.PushInterval. It returns when ctx is
+// done
+func (c *Client) Watch(ctx context.Context, repos []config.Repo, opts WatchOptions) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	projects := make([]*watched, 0, len(repos))
+	for _, repo := range repos {
+		if err := addRecursive(w, repo.Location); err != nil {
+			return fmt.Errorf("could not watch %s: %w", repo.Name, err)
+		}
+		projects = append(projects, &watched{repo: repo})
+		c.message("watching", repo.Name, "in", repo.Location)
+	}
+
+	check := time.NewTicker(checkInterval(opts.Settle))
+	defer check.Stop()
+
+	var push <-chan time.Time
+	if opts.PushInterval > 0 {
+		t := time.NewTicker(opts.PushInterval)
+		defer t.Stop()
+		push = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case e, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			p := owner(projects, e.Name)
+			if p == nil || isGitPath(p.repo.Location, e.Name) {
+				continue
+			}
+			p.changed = time.Now()
+			// new folders (e.g. a new alternative) need watching too
+			if e.Op&fsnotify.Create != 0 {
+				if fi, err := os.Stat(e.Name); err == nil && fi.IsDir() {
+					_ = addRecursive(w, e.Name)
+				}
+			}
+
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			c.message("WARNING: watching failed:", err.Error())
+
+		case now := <-check.C:
+			for _, p := range projects {
+				if p.changed.IsZero() || now.Sub(p.changed) < opts.Settle {
+					continue
+				}
+				// temporary files left over long ago (and kept out by the
+				// .gitignore) are not being written
+				if f := tempFile(p.repo.Location, p.changed.Add(-opts.Settle)); f != "" {
+					if p.waiting.IsZero() {
+						p.waiting = now
+					}
+					if now.Sub(p.waiting) < maxWaits*opts.Settle {
+						c.message("waiting for Logic to finish writing", f)
+						p.changed = now
+						continue
+					}
+					c.message("WARNING: Logic is still writing", f+", committing anyway")
+				}
+				p.changed, p.waiting = time.Time{}, time.Time{}
+				if err := c.autoCommit(p.repo); err != nil {
+					c.message("WARNING: could not commit", p.repo.Name+":", err.Error())
+				}
+			}
+
+		case <-push:
+			for _, p := range projects {
+				if ahead, _, err := c.AheadBehind(p.repo.Location); err != nil || ahead == 0 {
+					continue
+				}
+				if _, err := c.Git.Push(p.repo.Location); err != nil {
+					c.message("WARNING: could not upload", p.repo.Name+":", err.Error())
+					continue
+				}
+				c.message("uploaded", p.repo.Name)
+			}
+		}
+	}
+}
+
+// autoCommit commits the changes of repo picked by its selection with a
+// generated message
+func (c *Client) autoCommit(repo config.Repo) error {
+	if err := c.UpdateIgnore(repo); err != nil {
+		c.message("WARNING:", err)
+	}
+	if err := c.CheckLock(repo, ""); err != nil {
+		c.message("WARNING:", err)
+	}
+
+	changes, err := c.Git.Status(repo.Location)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	changes, paths, err := c.applySelection(repo, changes)
+	if err == ErrNothingSelected {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := c.CommitMessage(repo.Location, changes)
+	if err := c.commit(repo.Location, msg, paths); err != nil {
+		return err
+	}
+	c.message("committed", repo.Name+":", msg)
+	return nil
+}
+
+// checkInterval is how often pending changes are checked for having settled
+func checkInterval(settle time.Duration) time.Duration {
+	if d := settle / 4; d > 0 && d < time.Second {
+		return d
+	}
+	return time.Second
+}
+
+// addRecursive watches dir and its folders, except for the git internals
+func addRecursive(w *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		if fi.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return w.Add(p)
+	})
+}
+
+// owner returns the project file belongs to
+func owner(projects []*watched, file string) *watched {
+	for _, p := range projects {
+		if file == p.repo.Location || strings.HasPrefix(file, p.repo.Location+string(filepath.Separator)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// isGitPath tells whether file is within the git internals of repo, which
+// change on every commit
+func isGitPath(repo, file string) bool {
+	gitDir := filepath.Join(repo, ".git")
+	return file == gitDir || strings.HasPrefix(file, gitDir+string(filepath.Separator))
+}
+
+// tempFile returns a file Logic (or macOS, on its behalf) is still writing
+// within repo, if any: a temporary file modified after since
+func tempFile(repo string, since time.Time) string {
+	found := ""
+	_ = filepath.Walk(repo, func(p string, fi os.FileInfo, err error) error {
+		switch {
+		case err != nil:
+			return nil
+		case fi.IsDir() && fi.Name() == ".git":
+			return filepath.SkipDir
+		case isTempFile(fi.Name()) && fi.ModTime().After(since):
+			found = p
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+// errFound stops walking a project
+var errFound = errors.New("found")
+
+// isTempFile recognizes the files written while saving: macOS safe-save
+// files (ProjectData.sb-1a2b3c4d-XyZ123), temporary and lock files
+func isTempFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, ".sb-") ||
+		strings.HasSuffix(lower, ".tmp") ||
+		strings.HasSuffix(lower, ".temp") ||
+		strings.HasPrefix(lower, "~") ||
+		strings.HasPrefix(lower, ".~lock")
+}
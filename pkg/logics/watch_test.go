@@ -0,0 +1,130 @@
+package logics_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-watch")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	// as left by a previous upload
+	if _, err := logics.WriteIgnore(dir, logics.IgnorePatterns()); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	c, fake := newFakeClient("")
+	fake.Outputs["status --porcelain -z"] = " M song.logicx/Alternatives/000/ProjectData\x00?? Audio Files/Vox#01.wav\x00"
+
+	messages := make(chan string, 16)
+	c.OnMessage = func(msg string) { messages <- msg }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		assert.NoError(t, c.Watch(ctx, []config.Repo{{Name: "song", Location: dir}}, logics.WatchOptions{Settle: 100 * time.Millisecond}))
+		close(stopped)
+	}()
+	assert.Equal(t, "watching song in "+dir, <-messages)
+
+	// nothing gets committed while Logic is still saving
+	if !assert.NoError(t, ioutil.WriteFile(path.Join(dir, "ProjectData.sb-1a2b3c4d-XyZ123"), nil, 0644)) {
+		t.FailNow()
+	}
+	msg := <-messages
+	assert.True(t, strings.HasPrefix(msg, "waiting for Logic to finish writing"), msg)
+
+	if !assert.NoError(t, os.Remove(path.Join(dir, "ProjectData.sb-1a2b3c4d-XyZ123"))) {
+		t.FailNow()
+	}
+	for strings.HasPrefix(msg, "waiting") {
+		select {
+		case msg = <-messages:
+		case <-time.After(5 * time.Second):
+			t.Fatal("nothing committed")
+		}
+	}
+	assert.Equal(t, "committed song: Added recording Vox#01.wav, edited the arrangement", msg)
+
+	cancel()
+	<-stopped
+	assert.Equal(t, []string{
+		"status --porcelain -z",
+		"add -A .",
+		"commit -m Added recording Vox#01.wav, edited the arrangement",
+	}, fake.Commands())
+}
+
+func TestWatchTempFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logics-watch")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	// left over long ago
+	stale := path.Join(dir, "~ProjectData.tmp")
+	if !assert.NoError(t, ioutil.WriteFile(stale, nil, 0644)) {
+		t.FailNow()
+	}
+	old := time.Now().Add(-time.Hour)
+	assert.NoError(t, os.Chtimes(stale, old, old))
+	if _, err := logics.WriteIgnore(dir, logics.IgnorePatterns()); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	c, fake := newFakeClient("")
+	fake.Outputs["status --porcelain -z"] = " M song.logicx/Alternatives/000/ProjectData\x00"
+
+	messages := make(chan string, 64)
+	c.OnMessage = func(msg string) { messages <- msg }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.Watch(ctx, []config.Repo{{Name: "song", Location: dir}}, logics.WatchOptions{Settle: 50 * time.Millisecond})
+	}()
+	assert.Equal(t, "watching song in "+dir, <-messages)
+
+	next := func() string {
+		select {
+		case msg := <-messages:
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("nothing committed")
+			return ""
+		}
+	}
+
+	if !assert.NoError(t, ioutil.WriteFile(path.Join(dir, "ProjectData"), []byte("v2"), 0644)) {
+		t.FailNow()
+	}
+	assert.Equal(t, "committed song: Edited the arrangement", next())
+
+	// a temporary file which looks like being written forever does not
+	// block forever
+	writing := path.Join(dir, "ProjectData.sb-1a2b3c4d-XyZ123")
+	if !assert.NoError(t, ioutil.WriteFile(writing, nil, 0644)) {
+		t.FailNow()
+	}
+	future := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(writing, future, future))
+	msg := next()
+	for strings.HasPrefix(msg, "waiting") {
+		msg = next()
+	}
+	assert.Equal(t, "WARNING: Logic is still writing "+writing+", committing anyway", msg)
+	assert.Equal(t, "committed song: Edited the arrangement", next())
+}
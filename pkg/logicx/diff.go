@@ -0,0 +1,100 @@
+package logicx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Diff describes what changed musically from old to new, e.g. "tempo 120 →
+// 124 BPM". A nil old song means a new one
+func Diff(old, new *Song) []string {
+	if old == nil {
+		return []string{fmt.Sprintf("new song %s", new.Name())}
+	}
+
+	previous := make(map[int]Alternative, len(old.Alternatives))
+	for _, alt := range old.Alternatives {
+		previous[alt.Index] = alt
+	}
+
+	changes := make([]string, 0)
+	for _, alt := range new.Alternatives {
+		prev, ok := previous[alt.Index]
+		delete(previous, alt.Index)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("new alternative %s (%s)", alt.Label(), alt))
+			continue
+		}
+
+		prefix := ""
+		if len(new.Alternatives) > 1 {
+			prefix = alt.Label() + ": "
+		}
+		for _, c := range diffAlternative(prev, alt) {
+			changes = append(changes, prefix+c)
+		}
+	}
+
+	for _, alt := range old.Alternatives {
+		if _, ok := previous[alt.Index]; ok {
+			changes = append(changes, fmt.Sprintf("alternative %s removed", alt.Label()))
+		}
+	}
+	return changes
+}
+
+func diffAlternative(old, new Alternative) []string {
+	changes := make([]string, 0)
+	if old.Name != new.Name && old.Name != "" {
+		changes = append(changes, fmt.Sprintf("renamed from %s", old.Name))
+	}
+	if old.Tempo != new.Tempo {
+		changes = append(changes, fmt.Sprintf("tempo %s → %s", strconv.FormatFloat(old.Tempo, 'f', -1, 64), formatTempo(new.Tempo)))
+	}
+	if old.Key != new.Key {
+		changes = append(changes, fmt.Sprintf("key %s → %s", orNone(old.Key), orNone(new.Key)))
+	}
+	if old.TimeSignature != new.TimeSignature {
+		changes = append(changes, fmt.Sprintf("time signature %s → %s", orNone(old.TimeSignature), orNone(new.TimeSignature)))
+	}
+	if old.SampleRate != new.SampleRate {
+		changes = append(changes, fmt.Sprintf("sample rate %s → %s", formatSampleRate(old.SampleRate), formatSampleRate(new.SampleRate)))
+	}
+	if old.Tracks != new.Tracks {
+		changes = append(changes, fmt.Sprintf("tracks %d → %d", old.Tracks, new.Tracks))
+	}
+
+	added, removed := diffFiles(old.AudioFiles, new.AudioFiles)
+	if added > 0 {
+		changes = append(changes, fmt.Sprintf("%d audio file(s) added", added))
+	}
+	if removed > 0 {
+		changes = append(changes, fmt.Sprintf("%d audio file(s) removed", removed))
+	}
+	return changes
+}
+
+// diffFiles counts the files added to and removed from a list
+func diffFiles(old, new []string) (int, int) {
+	before := make(map[string]bool, len(old))
+	for _, f := range old {
+		before[f] = true
+	}
+
+	added := 0
+	for _, f := range new {
+		if before[f] {
+			delete(before, f)
+			continue
+		}
+		added++
+	}
+	return added, len(before)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
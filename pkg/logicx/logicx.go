@@ -0,0 +1,263 @@
+// Package logicx reads the metadata Logic stores within .logicx bundles:
+// tempo, key, time signature, sample rate, tracks and audio files of every
+// alternative of a song
+package logicx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/autholykos/logics/pkg/plist"
+)
+
+// Ext is the extension of Logic bundles
+const Ext = ".logicx"
+
+// FS is what bundles get read from: a folder on disk or a revision in git.
+// Names are slash separated and relative to its root
+type FS interface {
+	// ReadFile returns the content of the file name
+	ReadFile(name string) ([]byte, error)
+	// ReadDir returns the names of the entries of the folder name
+	ReadDir(name string) ([]string, error)
+}
+
+// Dir reads bundles from the folder it names
+type Dir string
+
+// ReadFile reads the file name within the folder
+func (d Dir) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+// ReadDir lists the folder name within the folder
+func (d Dir) ReadDir(name string) ([]string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(string(d), filepath.FromSlash(name)))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name()
+	}
+	return names, nil
+}
+
+// Song describes a Logic bundle
+type Song struct {
+	// Bundle is the path of the bundle within its FS
+	Bundle       string        `json:"bundle"`
+	Alternatives []Alternative `json:"alternatives"`
+}
+
+// Alternative describes an alternative (a version) of a song
+type Alternative struct {
+	Index            int      `json:"index"`
+	Name             string   `json:"name,omitempty"`
+	Tempo            float64  `json:"tempo"`
+	Key              string   `json:"key,omitempty"`
+	TimeSignature    string   `json:"timesignature,omitempty"`
+	SampleRate       int      `json:"samplerate"`
+	Tracks           int      `json:"tracks"`
+	AudioFiles       []string `json:"audiofiles,omitempty"`
+	UnusedAudioFiles []string `json:"unusedaudiofiles,omitempty"`
+}
+
+// String summarizes the alternative, e.g. "120 BPM, C major, 4/4, 44.1 kHz,
+// 12 tracks"
+func (a Alternative) String() string {
+	parts := make([]string, 0, 5)
+	if a.Tempo > 0 {
+		parts = append(parts, formatTempo(a.Tempo))
+	}
+	if a.Key != "" {
+		parts = append(parts, a.Key)
+	}
+	if a.TimeSignature != "" {
+		parts = append(parts, a.TimeSignature)
+	}
+	if a.SampleRate > 0 {
+		parts = append(parts, formatSampleRate(a.SampleRate))
+	}
+	parts = append(parts, fmt.Sprintf("%d tracks", a.Tracks))
+	return strings.Join(parts, ", ")
+}
+
+// Label names the alternative for humans
+func (a Alternative) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return fmt.Sprintf("alternative %d", a.Index)
+}
+
+// Name returns the name of the song, i.e. the bundle without extension
+func (s *Song) Name() string {
+	return strings.TrimSuffix(path.Base(s.Bundle), Ext)
+}
+
+// Find returns the bundles within the folder dir ("" being the root of
+// fsys), which is how Logic lays out project folders
+func Find(fsys FS, dir string) ([]string, error) {
+	names, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]string, 0)
+	for _, name := range names {
+		if strings.HasSuffix(name, Ext) {
+			bundles = append(bundles, path.Join(dir, name))
+		}
+	}
+	sort.Strings(bundles)
+	return bundles, nil
+}
+
+// Songs reads the bundles within the folder dir. Bundles which cannot be read
+// (e.g. not synced yet) are left out
+func Songs(fsys FS, dir string) ([]*Song, error) {
+	bundles, err := Find(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]*Song, 0, len(bundles))
+	for _, bundle := range bundles {
+		if s, err := Read(fsys, bundle); err == nil {
+			songs = append(songs, s)
+		}
+	}
+	return songs, nil
+}
+
+// Read reads the metadata of the bundle
+func Read(fsys FS, bundle string) (*Song, error) {
+	entries, err := fsys.ReadDir(path.Join(bundle, "Alternatives"))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a Logic bundle: %w", bundle, err)
+	}
+	names := variantNames(fsys, bundle)
+
+	song := &Song{Bundle: bundle, Alternatives: make([]Alternative, 0, len(entries))}
+	for _, entry := range entries {
+		index, err := strconv.Atoi(entry)
+		if err != nil {
+			continue
+		}
+
+		data, err := fsys.ReadFile(path.Join(bundle, "Alternatives", entry, "MetaData.plist"))
+		if err != nil {
+			continue
+		}
+		alt, err := parseMetaData(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata of %s alternative %s: %w", bundle, entry, err)
+		}
+		alt.Index = index
+		alt.Name = names[index]
+		song.Alternatives = append(song.Alternatives, *alt)
+	}
+
+	sort.Slice(song.Alternatives, func(i, j int) bool {
+		return song.Alternatives[i].Index < song.Alternatives[j].Index
+	})
+	return song, nil
+}
+
+// variantNames returns the names of the alternatives by index. Bundles
+// without names are fine
+func variantNames(fsys FS, bundle string) map[int]string {
+	names := make(map[int]string)
+	data, err := fsys.ReadFile(path.Join(bundle, "Resources", "ProjectInformation.plist"))
+	if err != nil {
+		return names
+	}
+	v, err := plist.Parse(data)
+	if err != nil {
+		return names
+	}
+	info, _ := v.(map[string]interface{})
+
+	switch variants := info["VariantNames"].(type) {
+	case map[string]interface{}:
+		for k, v := range variants {
+			if i, err := strconv.Atoi(k); err == nil {
+				names[i], _ = v.(string)
+			}
+		}
+	case []interface{}:
+		for i, v := range variants {
+			names[i], _ = v.(string)
+		}
+	}
+	return names
+}
+
+func parseMetaData(data []byte) (*Alternative, error) {
+	v, err := plist.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected metadata %T", v)
+	}
+
+	alt := &Alternative{
+		Tempo:            number(meta["BeatsPerMinute"]),
+		SampleRate:       int(number(meta["SampleRate"])),
+		Tracks:           int(number(meta["NumberOfTracks"])),
+		AudioFiles:       stringList(meta["AudioFiles"]),
+		UnusedAudioFiles: stringList(meta["UnusedAudioFiles"]),
+	}
+
+	key, _ := meta["SongKey"].(string)
+	gender, _ := meta["SongGenderKey"].(string)
+	alt.Key = strings.TrimSpace(key + " " + gender)
+
+	num, den := number(meta["SongSignatureNumerator"]), number(meta["SongSignatureDenominator"])
+	if num > 0 && den > 0 {
+		alt.TimeSignature = fmt.Sprintf("%d/%d", int(num), int(den))
+	}
+	return alt, nil
+}
+
+// number returns the value of plist integers and reals
+func number(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// stringList returns the strings within a plist array
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func formatTempo(bpm float64) string {
+	return strconv.FormatFloat(bpm, 'f', -1, 64) + " BPM"
+}
+
+func formatSampleRate(rate int) string {
+	return strconv.FormatFloat(float64(rate)/1000, 'f', -1, 64) + " kHz"
+}
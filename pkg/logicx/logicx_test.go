@@ -0,0 +1,68 @@
+package logicx_test
+
+import (
+	"testing"
+
+	"github.com/autholykos/logics/pkg/logicx"
+	"github.com/stretchr/testify/assert"
+)
+
+var song = &logicx.Song{
+	Bundle: "song.logicx",
+	Alternatives: []logicx.Alternative{
+		{
+			Index:            0,
+			Name:             "song",
+			Tempo:            120,
+			Key:              "C major",
+			TimeSignature:    "4/4",
+			SampleRate:       44100,
+			Tracks:           12,
+			AudioFiles:       []string{"Audio Files/Vox#01.wav", "Audio Files/Bass#01.wav"},
+			UnusedAudioFiles: []string{"Audio Files/Vox#00.wav"},
+		},
+		{
+			Index:         1,
+			Name:          "song - acoustic",
+			Tempo:         92.5,
+			Key:           "A minor",
+			TimeSignature: "6/8",
+			SampleRate:    48000,
+			Tracks:        4,
+		},
+	},
+}
+
+func TestSongs(t *testing.T) {
+	songs, err := logicx.Songs(logicx.Dir("testdata"), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []*logicx.Song{song}, songs)
+	assert.Equal(t, "song", songs[0].Name())
+	assert.Equal(t, "120 BPM, C major, 4/4, 44.1 kHz, 12 tracks", songs[0].Alternatives[0].String())
+}
+
+func TestReadNotABundle(t *testing.T) {
+	_, err := logicx.Read(logicx.Dir("testdata"), "missing.logicx")
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	changed := &logicx.Song{Bundle: "song.logicx", Alternatives: []logicx.Alternative{song.Alternatives[0]}}
+	changed.Alternatives[0].Tempo = 124
+	changed.Alternatives[0].Tracks = 13
+	changed.Alternatives[0].AudioFiles = []string{"Audio Files/Vox#01.wav", "Audio Files/Vox#02.wav", "Audio Files/Bass#01.wav"}
+
+	assert.Equal(t, []string{
+		"tempo 120 → 124 BPM",
+		"tracks 12 → 13",
+		"1 audio file(s) added",
+		"alternative song - acoustic removed",
+	}, logicx.Diff(song, changed))
+
+	assert.Equal(t, []string{"new alternative song - acoustic (92.5 BPM, A minor, 6/8, 48 kHz, 4 tracks)"}, logicx.Diff(changed, song)[3:])
+	assert.Equal(t, []string{"new song song"}, logicx.Diff(nil, song))
+	assert.Empty(t, logicx.Diff(song, song))
+}
@@ -0,0 +1,101 @@
+// Package notify tells the user about things happening in the background,
+// e.g. through desktop notifications
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/autholykos/logics/pkg/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(title, body string) error
+}
+
+// Writer writes every notification as a line, e.g. to stdout
+type Writer struct {
+	W io.Writer
+}
+
+// Notify writes the notification
+func (n Writer) Notify(title, body string) error {
+	_, err := fmt.Fprintf(n.W, "%s: %s\n", title, body)
+	return err
+}
+
+// Log logs every notification, which is enough for headless machines
+type Log struct{}
+
+// Notify logs the notification
+func (Log) Notify(title, body string) error {
+	log.WithField("title", title).Infoln(body)
+	return nil
+}
+
+// Desktop shows desktop notifications through osascript on macOS and
+// notify-send on linux
+type Desktop struct {
+	args func(title, body string) []string
+}
+
+// NewDesktop returns a Desktop notifier, or an error if this machine cannot
+// show desktop notifications
+func NewDesktop() (*Desktop, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &Desktop{func(title, body string) []string {
+			// passing the texts as arguments spares quoting them
+			return []string{"osascript",
+				"-e", "on run argv",
+				"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+				"-e", "end run",
+				title, body}
+		}}, nil
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil, errors.New("notify-send not found")
+		}
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return nil, errors.New("no graphical session")
+		}
+		return &Desktop{func(title, body string) []string {
+			return []string{"notify-send", title, body}
+		}}, nil
+	default:
+		return nil, fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+}
+
+// Notify shows the notification
+func (n *Desktop) Notify(title, body string) error {
+	args := n.args(title, body)
+	_, err := common.ExecCmd(args[0], args[1:]...)
+	return err
+}
+
+// New returns the notifier of the given kind: desktop, stdout (written to
+// w), log or auto, which picks desktop when available and stdout otherwise
+func New(kind string, w io.Writer) (Notifier, error) {
+	switch kind {
+	case "desktop":
+		return NewDesktop()
+	case "stdout":
+		return Writer{w}, nil
+	case "log":
+		return Log{}, nil
+	case "auto":
+		if n, err := NewDesktop(); err == nil {
+			return n, nil
+		}
+		return Writer{w}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %s (use auto, desktop, stdout or log)", kind)
+	}
+}
@@ -0,0 +1,31 @@
+package notify_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := notify.New("stdout", &buf)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.NoError(t, n.Notify("capelli-curti", "2 new commits by Pippo"))
+	assert.Equal(t, "capelli-curti: 2 new commits by Pippo\n", buf.String())
+}
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{"auto", "stdout", "log"} {
+		n, err := notify.New(kind, &bytes.Buffer{})
+		assert.NoError(t, err, kind)
+		assert.NotNil(t, n, kind)
+	}
+
+	_, err := notify.New("pigeon", &bytes.Buffer{})
+	assert.Error(t, err)
+}
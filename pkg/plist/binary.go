@@ -0,0 +1,286 @@
+package plist
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	binaryMagic = "bplist00"
+	trailerSize = 32
+	// maxDepth guards against maliciously nested lists
+	maxDepth = 64
+	// maxDecoded guards against objects referenced over and over
+	maxDecoded = 1 << 20
+)
+
+// appleEpoch is the origin of the plist dates
+var appleEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type binaryParser struct {
+	data       []byte
+	offsets    []uint64
+	refSize    int
+	objectsEnd uint64
+
+	// decoding holds the lists being decoded, to detect cycles
+	decoding map[uint64]bool
+	decoded  int
+}
+
+func parseBinary(data []byte) (interface{}, error) {
+	if len(data) < len(binaryMagic)+trailerSize {
+		return nil, ErrFormat
+	}
+
+	trailer := data[len(data)-trailerSize:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+
+	tableEnd := uint64(len(data) - trailerSize)
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 ||
+		numObjects == 0 || top >= numObjects || tableOffset > tableEnd ||
+		numObjects > (tableEnd-tableOffset)/uint64(offsetSize) {
+		return nil, fmt.Errorf("%w: corrupted trailer", ErrFormat)
+	}
+
+	p := &binaryParser{data: data, refSize: refSize, objectsEnd: tableOffset, decoding: make(map[uint64]bool)}
+	p.offsets = make([]uint64, numObjects)
+	for i := range p.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+	}
+	return p.object(top, 0)
+}
+
+func readUint(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
+
+// bytes returns n bytes at off, within the objects
+func (p *binaryParser) bytes(off, n uint64) ([]byte, error) {
+	if off > p.objectsEnd || n > p.objectsEnd-off {
+		return nil, fmt.Errorf("%w: object out of bounds", ErrFormat)
+	}
+	return p.data[off : off+n], nil
+}
+
+// length returns the length encoded in the marker low nibble, possibly
+// followed by an int object, and where the content starts
+func (p *binaryParser) length(off uint64, nibble byte) (uint64, uint64, error) {
+	if nibble != 0x0F {
+		return uint64(nibble), off + 1, nil
+	}
+	b, err := p.bytes(off+1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]>>4 != 0x1 {
+		return 0, 0, fmt.Errorf("%w: bad length marker", ErrFormat)
+	}
+	size := uint64(1) << (b[0] & 0x0F)
+	if size > 8 {
+		return 0, 0, fmt.Errorf("%w: length too big", ErrFormat)
+	}
+	lb, err := p.bytes(off+2, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return readUint(lb), off + 2 + size, nil
+}
+
+// refs reads n object references at off
+func (p *binaryParser) refs(off, n uint64) ([]uint64, error) {
+	if n > p.objectsEnd/uint64(p.refSize) {
+		return nil, fmt.Errorf("%w: too many references", ErrFormat)
+	}
+	b, err := p.bytes(off, n*uint64(p.refSize))
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, n)
+	for i := range refs {
+		refs[i] = readUint(b[i*p.refSize : (i+1)*p.refSize])
+	}
+	return refs, nil
+}
+
+func (p *binaryParser) object(ref uint64, depth int) (interface{}, error) {
+	if ref >= uint64(len(p.offsets)) {
+		return nil, fmt.Errorf("%w: bad object reference", ErrFormat)
+	}
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: too deeply nested", ErrFormat)
+	}
+	if p.decoded++; p.decoded > maxDecoded {
+		return nil, fmt.Errorf("%w: too many objects", ErrFormat)
+	}
+
+	off := p.offsets[ref]
+	m, err := p.bytes(off, 1)
+	if err != nil {
+		return nil, err
+	}
+	marker, nibble := m[0]>>4, m[0]&0x0F
+
+	switch marker {
+	case 0x0:
+		switch nibble {
+		case 0x8:
+			return false, nil
+		case 0x9:
+			return true, nil
+		default:
+			return nil, nil
+		}
+
+	case 0x1:
+		size := uint64(1) << nibble
+		b, err := p.bytes(off+1, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 1, 2, 4, 8:
+			// ints up to 4 bytes are unsigned, 8 bytes ones are signed:
+			// both fit an int64
+			return int64(readUint(b)), nil
+		case 16:
+			// 128 bits ints only hold 64 bits values in practice
+			return int64(readUint(b[8:])), nil
+		}
+		return nil, fmt.Errorf("%w: bad int size %d", ErrFormat, size)
+
+	case 0x2:
+		size := uint64(1) << nibble
+		b, err := p.bytes(off+1, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+		}
+		return nil, fmt.Errorf("%w: bad real size %d", ErrFormat, size)
+
+	case 0x3:
+		b, err := p.bytes(off+1, 8)
+		if err != nil {
+			return nil, err
+		}
+		secs := math.Float64frombits(binary.BigEndian.Uint64(b))
+		return appleEpoch.Add(time.Duration(secs * float64(time.Second))), nil
+
+	case 0x4, 0x5:
+		n, start, err := p.length(off, nibble)
+		if err != nil {
+			return nil, err
+		}
+		b, err := p.bytes(start, n)
+		if err != nil {
+			return nil, err
+		}
+		if marker == 0x5 {
+			return string(b), nil
+		}
+		return append([]byte(nil), b...), nil
+
+	case 0x6:
+		n, start, err := p.length(off, nibble)
+		if err != nil {
+			return nil, err
+		}
+		if n > p.objectsEnd {
+			return nil, fmt.Errorf("%w: string too long", ErrFormat)
+		}
+		b, err := p.bytes(start, 2*n)
+		if err != nil {
+			return nil, err
+		}
+		units := make([]uint16, n)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+
+	case 0x8:
+		b, err := p.bytes(off+1, uint64(nibble)+1)
+		if err != nil {
+			return nil, err
+		}
+		return UID(readUint(b)), nil
+
+	case 0xA, 0xD:
+		if p.decoding[ref] {
+			return nil, fmt.Errorf("%w: cyclic reference", ErrFormat)
+		}
+		p.decoding[ref] = true
+		defer delete(p.decoding, ref)
+		if marker == 0xD {
+			return p.dict(off, nibble, depth)
+		}
+		return p.array(off, nibble, depth)
+	}
+
+	return nil, fmt.Errorf("%w: unknown marker %#x", ErrFormat, m[0])
+}
+
+func (p *binaryParser) array(off uint64, nibble byte, depth int) (interface{}, error) {
+	n, start, err := p.length(off, nibble)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := p.refs(start, n)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, n)
+	for i, r := range refs {
+		if list[i], err = p.object(r, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (p *binaryParser) dict(off uint64, nibble byte, depth int) (interface{}, error) {
+	n, start, err := p.length(off, nibble)
+	if err != nil {
+		return nil, err
+	}
+	// keys and values are referenced in a row: doubling n must not overflow
+	if n > p.objectsEnd/uint64(2*p.refSize) {
+		return nil, fmt.Errorf("%w: too many references", ErrFormat)
+	}
+	refs, err := p.refs(start, 2*n)
+	if err != nil {
+		return nil, err
+	}
+	dict := make(map[string]interface{}, n)
+	for i := uint64(0); i < n; i++ {
+		k, err := p.object(refs[i], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: non string key", ErrFormat)
+		}
+		if dict[key], err = p.object(refs[n+i], depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return dict, nil
+}
@@ -0,0 +1,24 @@
+// Package plist decodes property lists, both binary (bplist00) and XML, the
+// formats Logic stores its project metadata in. Values decode to
+// map[string]interface{}, []interface{}, string, int64, float64, bool,
+// time.Time, []byte and UID
+package plist
+
+import (
+	"bytes"
+	"errors"
+)
+
+// UID is a reference used by keyed archives
+type UID uint64
+
+// ErrFormat is returned for data which is not a property list
+var ErrFormat = errors.New("not a property list")
+
+// Parse decodes a binary or XML property list
+func Parse(data []byte) (interface{}, error) {
+	if bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return parseBinary(data)
+	}
+	return parseXML(data)
+}
@@ -0,0 +1,86 @@
+package plist_test
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/autholykos/logics/pkg/plist"
+	"github.com/stretchr/testify/assert"
+)
+
+var sample = map[string]interface{}{
+	"BeatsPerMinute": 123.5,
+	"SampleRate":     int64(44100),
+	"Offset":         int64(-3),
+	"Big":            int64(1 << 40),
+	"SongKey":        "C",
+	"Title":          "Capelli Curti – Née",
+	"Flag":           true,
+	"Off":            false,
+	"Data":           []byte{0, 1, 2},
+	"Saved":          time.Date(2020, 2, 20, 10, 0, 0, 0, time.UTC),
+	"AudioFiles":     []interface{}{"Vox#01.wav", "Bass#02.wav"},
+	"Nested": map[string]interface{}{
+		"Empty": []interface{}{},
+		"Deep":  map[string]interface{}{"k": "v"},
+	},
+}
+
+func TestParse(t *testing.T) {
+	for _, file := range []string{"testdata/sample.bplist", "testdata/sample.plist"} {
+		data, err := ioutil.ReadFile(file)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		v, err := plist.Parse(data)
+		if !assert.NoError(t, err, file) {
+			continue
+		}
+
+		m := v.(map[string]interface{})
+		// dates compare by instant, regardless of the location
+		assert.True(t, sample["Saved"].(time.Time).Equal(m["Saved"].(time.Time)), file)
+		m["Saved"] = sample["Saved"]
+		assert.Equal(t, sample, m, file)
+	}
+}
+
+func TestParseCorrupted(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/sample.bplist")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for _, corrupted := range [][]byte{
+		nil,
+		[]byte("bplist00"),
+		data[:len(data)-1],
+		append(append([]byte{}, data[:len(data)-8]...), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+		// an array containing itself
+		append([]byte("bplist00\xa1\x00\x08"), 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10),
+		// a dict whose length overflows once doubled
+		append([]byte("bplist00\xdf\x13\x80\x00\x00\x00\x00\x00\x00\x01\x01\x01\x51a\x08\x14"), 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 22),
+		[]byte("<html></html>"),
+		[]byte("<plist><dict><key>a</key></dict></plist>"),
+	} {
+		_, err := plist.Parse(corrupted)
+		assert.Error(t, err)
+	}
+}
+
+func TestParseNeverPanics(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/sample.bplist")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for i := range data {
+		_, _ = plist.Parse(data[:i])
+
+		flipped := append([]byte{}, data...)
+		flipped[i] ^= 0xff
+		_, _ = plist.Parse(flipped)
+	}
+}
@@ -0,0 +1,135 @@
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func parseXML(data []byte) (interface{}, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	// plists declare the DTD but do not need it
+	d.Strict = false
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, ErrFormat
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "plist" {
+				return nil, ErrFormat
+			}
+			return nextValue(d, "plist", 0)
+		}
+	}
+}
+
+// nextValue decodes the value following within the element parent, which is
+// expected to contain exactly one
+func nextValue(d *xml.Decoder, parent string, depth int) (interface{}, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return value(d, t, depth)
+		case xml.EndElement:
+			return nil, fmt.Errorf("%w: empty %s", ErrFormat, parent)
+		}
+	}
+}
+
+// value decodes the element started by start
+func value(d *xml.Decoder, start xml.StartElement, depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: too deeply nested", ErrFormat)
+	}
+
+	switch start.Name.Local {
+	case "dict":
+		return dict(d, depth)
+	case "array":
+		return array(d, depth)
+	case "true", "false":
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	text = strings.TrimSpace(text)
+
+	switch start.Name.Local {
+	case "string", "key":
+		return text, nil
+	case "integer":
+		return strconv.ParseInt(text, 10, 64)
+	case "real":
+		return strconv.ParseFloat(text, 64)
+	case "date":
+		return time.Parse(time.RFC3339, text)
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	}
+	return nil, fmt.Errorf("%w: unknown element %s", ErrFormat, start.Name.Local)
+}
+
+func dict(d *xml.Decoder, depth int) (interface{}, error) {
+	m := make(map[string]interface{})
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return m, nil
+		case xml.StartElement:
+			if t.Name.Local != "key" {
+				return nil, fmt.Errorf("%w: %s instead of key", ErrFormat, t.Name.Local)
+			}
+			var key string
+			if err := d.DecodeElement(&key, &t); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+			}
+			if m[key], err = nextValue(d, "dict", depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func array(d *xml.Decoder, depth int) (interface{}, error) {
+	list := make([]interface{}, 0)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return list, nil
+		case xml.StartElement:
+			v, err := value(d, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// progress is the package tracking and rendering the progress of git-lfs
+// transfers, as reported through the GIT_LFS_PROGRESS file
+package progress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Event is a line of the GIT_LFS_PROGRESS file, reporting the progress of a
+// single file, e.g.
+//
+//   download 3/12 1048576/52428800 Audio Files/Vox_Take4.wav
+type Event struct {
+	// Direction is either download, upload or checkout
+	Direction string
+	// File is the position of the file among Files
+	File  int
+	Files int
+	// Bytes of the file transferred so far, out of Size
+	Bytes int64
+	Size  int64
+	Name  string
+}
+
+// ParseLine parses a line written by git-lfs in the GIT_LFS_PROGRESS file
+func ParseLine(line string) (Event, error) {
+	e := Event{}
+	fields := strings.SplitN(strings.TrimSpace(line), " ", 4)
+	if len(fields) != 4 {
+		return e, fmt.Errorf("invalid progress line: %s", line)
+	}
+
+	e.Direction, e.Name = fields[0], fields[3]
+
+	var err error
+	if e.File, e.Files, err = parseFraction(fields[1]); err != nil {
+		return e, err
+	}
+
+	bytes, size, err := parseFraction(fields[2])
+	e.Bytes, e.Size = int64(bytes), int64(size)
+	return e, err
+}
+
+func parseFraction(s string) (int, int, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid progress fraction: %s", s)
+	}
+
+	n, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	d, err := strconv.Atoi(parts[1])
+	return n, d, err
+}
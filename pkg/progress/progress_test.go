@@ -0,0 +1,78 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLine(t *testing.T) {
+	e, err := ParseLine("download 3/12 1048576/52428800 Audio Files/Vox Take4.wav\n")
+	assert.NoError(t, err)
+	assert.Equal(t, Event{"download", 3, 12, 1048576, 52428800, "Audio Files/Vox Take4.wav"}, e)
+
+	for _, line := range []string{"", "download 3/12", "upload x/12 1/2 a.wav", "upload 1/2 1-2 a.wav"} {
+		_, err := ParseLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := newTracker(func() time.Time { return now })
+	assert.Nil(t, tracker.Stats().Current)
+
+	tracker.Update(Event{"upload", 1, 4, 100, 100, "a.wav"})
+	tracker.Update(Event{"upload", 2, 4, 50, 100, "b.wav"})
+	now = now.Add(10 * time.Second)
+
+	s := tracker.Stats()
+	assert.Equal(t, "upload", s.Direction)
+	assert.Equal(t, 4, s.Files)
+	assert.Equal(t, 1, s.FilesDone)
+	assert.Equal(t, int64(150), s.Bytes)
+	assert.Equal(t, int64(200), s.TotalBytes)
+	assert.Equal(t, "b.wav", s.Current.Name)
+	assert.Equal(t, 15.0, s.Throughput)
+	// 50 bytes left of b.wav and two more files of 100 bytes each
+	assert.Equal(t, 250*time.Second/15, s.ETA)
+}
+
+func TestWatch(t *testing.T) {
+	f, err := ioutil.TempFile("", "logics-lfs-progress")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.Remove(f.Name())
+
+	ctx, stop := context.WithCancel(context.Background())
+	tracker := NewTracker()
+	var out bytes.Buffer
+	r := NewLogRenderer(&out, time.Hour)
+
+	watched := make(chan struct{})
+	go func() {
+		Watch(ctx, f.Name(), tracker, r, 10*time.Millisecond)
+		close(watched)
+	}()
+
+	_, _ = f.WriteString("download 1/2 10/10 a.wav\ndownload 2/2 5/")
+	time.Sleep(50 * time.Millisecond)
+	_, _ = f.WriteString("20 b.wav\n")
+	_ = f.Close()
+
+	stop()
+	<-watched
+	s := tracker.Stats()
+	assert.Equal(t, int64(15), s.Bytes)
+	assert.Equal(t, int64(30), s.TotalBytes)
+	assert.Equal(t, "b.wav", s.Current.Name)
+
+	r.Done(s)
+	assert.Contains(t, out.String(), "download: 1/2 files, 15 B/30 B")
+}
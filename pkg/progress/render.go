@@ -0,0 +1,167 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	barWidth  = 30
+	nameWidth = 32
+)
+
+// Renderer displays the progress of a transfer
+type Renderer interface {
+	// Render is called periodically while the transfer is running
+	Render(s Stats)
+	// Done is called once the transfer is over
+	Done(s Stats)
+}
+
+// NopRenderer hides the progress, e.g. with --quiet
+type NopRenderer struct{}
+
+func (NopRenderer) Render(Stats) {}
+func (NopRenderer) Done(Stats)   {}
+
+// Auto returns how to render the progress on f, and how often: bars on
+// terminals, periodic log lines otherwise and nothing at all when quiet
+func Auto(f *os.File, quiet bool) (Renderer, time.Duration) {
+	switch {
+	case quiet:
+		return NopRenderer{}, time.Second
+	case isatty.IsTerminal(f.Fd()):
+		return NewBarRenderer(f), 200 * time.Millisecond
+	default:
+		return NewLogRenderer(f, 10*time.Second), time.Second
+	}
+}
+
+// BarRenderer redraws a bar for the current file and a bar for the whole
+// transfer. It is meant for terminals
+type BarRenderer struct {
+	w     io.Writer
+	drawn bool
+}
+
+// NewBarRenderer creates a BarRenderer writing on w
+func NewBarRenderer(w io.Writer) *BarRenderer {
+	return &BarRenderer{w: w}
+}
+
+// Render redraws the bars in place
+func (r *BarRenderer) Render(s Stats) {
+	if s.Current == nil {
+		return
+	}
+
+	if r.drawn {
+		// back to the beginning of the first bar
+		fmt.Fprint(r.w, "\r\033[1A")
+	}
+	r.drawn = true
+
+	c := s.Current
+	fmt.Fprintf(r.w, "\r\033[K%-*s %s %3.0f%%\n", nameWidth, truncate(c.Name, nameWidth), bar(c.Bytes, c.Size), percent(c.Bytes, c.Size))
+	fmt.Fprintf(r.w, "\033[K%-*s %s %3.0f%% %s/%s %s/s ETA %s",
+		nameWidth, fmt.Sprintf("%s %d/%d files", s.Direction, s.FilesDone, s.Files),
+		bar(s.Bytes, s.TotalBytes), percent(s.Bytes, s.TotalBytes),
+		HumanBytes(s.Bytes), HumanBytes(s.TotalBytes), HumanBytes(int64(s.Throughput)), eta(s.ETA))
+}
+
+// Done draws the final state of the bars
+func (r *BarRenderer) Done(s Stats) {
+	if s.Current == nil {
+		return
+	}
+	r.Render(s)
+	fmt.Fprintln(r.w)
+}
+
+// LogRenderer writes a line with the progress at most every interval. It is
+// meant for logs and pipes, where bars cannot be redrawn
+type LogRenderer struct {
+	w        io.Writer
+	interval time.Duration
+	last     time.Time
+}
+
+// NewLogRenderer creates a LogRenderer writing on w
+func NewLogRenderer(w io.Writer, interval time.Duration) *LogRenderer {
+	return &LogRenderer{w: w, interval: interval}
+}
+
+// Render writes a line if the interval since the last one expired
+func (r *LogRenderer) Render(s Stats) {
+	if s.Current == nil || time.Since(r.last) < r.interval {
+		return
+	}
+	r.last = time.Now()
+	r.line(s)
+}
+
+// Done writes the final progress line
+func (r *LogRenderer) Done(s Stats) {
+	if s.Current == nil {
+		return
+	}
+	r.line(s)
+}
+
+func (r *LogRenderer) line(s Stats) {
+	fmt.Fprintf(r.w, "%s: %d/%d files, %s/%s (%.0f%%), %s/s, ETA %s, current: %s\n",
+		s.Direction, s.FilesDone, s.Files, HumanBytes(s.Bytes), HumanBytes(s.TotalBytes),
+		percent(s.Bytes, s.TotalBytes), HumanBytes(int64(s.Throughput)), eta(s.ETA), s.Current.Name)
+}
+
+func percent(n, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+func bar(n, total int64) string {
+	filled := 0
+	if total > 0 {
+		filled = int(float64(n) / float64(total) * barWidth)
+	}
+	if filled > barWidth {
+		filled = barWidth
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "…" + s[len(s)-n+1:]
+}
+
+func eta(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
+// HumanBytes renders a size in bytes with a binary unit
+func HumanBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
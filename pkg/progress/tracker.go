@@ -0,0 +1,95 @@
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of the progress of a transfer
+type Stats struct {
+	Direction string
+	// Files is the number of files to transfer, FilesDone the number of
+	// files completely transferred
+	Files     int
+	FilesDone int
+	// Bytes transferred so far, out of the TotalBytes of the files seen so
+	// far (git-lfs does not announce the size of the files ahead)
+	Bytes      int64
+	TotalBytes int64
+	// Current is the file that progressed last
+	Current *Event
+	// Throughput is in bytes per second
+	Throughput float64
+	// ETA is an estimate of the time left, or zero if unknown
+	ETA     time.Duration
+	Elapsed time.Duration
+}
+
+// Tracker aggregates the progress events of the files being transferred
+type Tracker struct {
+	mu      sync.Mutex
+	start   time.Time
+	now     func() time.Time
+	files   map[string]Event
+	current *Event
+}
+
+// NewTracker creates a Tracker starting now
+func NewTracker() *Tracker {
+	return newTracker(time.Now)
+}
+
+func newTracker(now func() time.Time) *Tracker {
+	return &Tracker{
+		start: now(),
+		now:   now,
+		files: make(map[string]Event),
+	}
+}
+
+// Update records an event
+func (t *Tracker) Update(e Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.files[e.Direction+" "+e.Name] = e
+	t.current = &e
+}
+
+// Stats returns a snapshot of the progress so far
+func (t *Tracker) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := Stats{Elapsed: t.now().Sub(t.start)}
+	if t.current == nil {
+		return s
+	}
+
+	current := *t.current
+	s.Current = &current
+	s.Direction = current.Direction
+	for _, e := range t.files {
+		if e.Files > s.Files {
+			s.Files = e.Files
+		}
+		s.Bytes += e.Bytes
+		s.TotalBytes += e.Size
+		if e.Bytes == e.Size {
+			s.FilesDone++
+		}
+	}
+
+	if secs := s.Elapsed.Seconds(); secs > 0 {
+		s.Throughput = float64(s.Bytes) / secs
+	}
+
+	// files not seen yet are assumed as big as the average file seen so far
+	remaining := float64(s.TotalBytes - s.Bytes)
+	if seen := len(t.files); seen > 0 && s.Files > seen {
+		remaining += float64(s.TotalBytes) / float64(seen) * float64(s.Files-seen)
+	}
+	if s.Throughput > 0 {
+		s.ETA = time.Duration(remaining / s.Throughput * float64(time.Second))
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package progress
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Watch follows the GIT_LFS_PROGRESS file at path, feeding the tracker and
+// rendering its progress every interval, until ctx is done. The file does
+// not need to exist yet: git-lfs creates it on the first transfer. Whatever
+// got written before ctx was done is read before returning
+func Watch(ctx context.Context, path string, t *Tracker, r Renderer, interval time.Duration) {
+	w := &watcher{path: path, tracker: t}
+	defer w.close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.read()
+		r.Render(t.Stats())
+
+		select {
+		case <-ctx.Done():
+			w.read()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type watcher struct {
+	path    string
+	tracker *Tracker
+	f       *os.File
+	reader  *bufio.Reader
+	partial string
+}
+
+// read feeds the tracker with the lines written since the last read
+func (w *watcher) read() {
+	if w.f == nil {
+		f, err := os.Open(w.path)
+		if err != nil {
+			return
+		}
+		w.f, w.reader = f, bufio.NewReader(f)
+	}
+
+	for {
+		line, err := w.reader.ReadString('\n')
+		if err == io.EOF {
+			// keep what has been written of an incomplete line
+			w.partial += line
+			return
+		}
+		if err != nil {
+			log.WithError(err).Debugln("could not read the lfs progress file")
+			return
+		}
+
+		line, w.partial = w.partial+line, ""
+		e, err := ParseLine(line)
+		if err != nil {
+			log.WithError(err).Debugln("skipping lfs progress line")
+			continue
+		}
+		w.tracker.Update(e)
+	}
+}
+
+func (w *watcher) close() {
+	if w.f != nil {
+		_ = w.f.Close()
+	}
+}
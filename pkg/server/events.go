@@ -0,0 +1,103 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/autholykos/logics/pkg/progress"
+)
+
+// Event is what front-ends receive through /api/events
+type Event struct {
+	// Type is one of message, progress, done and error
+	Type    string      `json:"type"`
+	Project string      `json:"project,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// Progress is the Data of progress events
+type Progress struct {
+	Direction  string  `json:"direction"`
+	Files      int     `json:"files"`
+	FilesDone  int     `json:"filesdone"`
+	Bytes      int64   `json:"bytes"`
+	TotalBytes int64   `json:"totalbytes"`
+	File       string  `json:"file,omitempty"`
+	Throughput float64 `json:"throughput"`
+	// ETA is in seconds, zero if unknown
+	ETA  float64 `json:"eta"`
+	Done bool    `json:"done"`
+}
+
+// subscriberBuffer is how many events a slow front-end can lag behind before
+// it starts missing them
+const subscriberBuffer = 64
+
+// broker fans the events out to the subscribed front-ends
+type broker struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subs: make(map[chan Event]struct{})}
+}
+
+func (b *broker) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+// publish never blocks: operations must not wait for the front-ends
+func (b *broker) publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// progressRenderer publishes the progress of a transfer
+type progressRenderer struct {
+	s *Server
+}
+
+func (r progressRenderer) Render(s progress.Stats) {
+	r.s.publish("progress", toProgress(s, false))
+}
+
+func (r progressRenderer) Done(s progress.Stats) {
+	r.s.publish("progress", toProgress(s, true))
+}
+
+func toProgress(s progress.Stats, done bool) Progress {
+	p := Progress{
+		Direction:  s.Direction,
+		Files:      s.Files,
+		FilesDone:  s.FilesDone,
+		Bytes:      s.Bytes,
+		TotalBytes: s.TotalBytes,
+		Throughput: s.Throughput,
+		ETA:        s.ETA.Seconds(),
+		Done:       done,
+	}
+	if s.Current != nil {
+		p.File = s.Current.Name
+	}
+	return p
+}
+
+// progressInterval is how often front-ends get notified of transfers
+const progressInterval = 500 * time.Millisecond
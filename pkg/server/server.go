@@ -0,0 +1,404 @@
+// Package server exposes the logics operations through a local HTTP/JSON API,
+// so that front-ends can drive logics without shelling out to the CLI. Every
+// request must carry the token, either as `Authorization: Bearer <token>` or
+// (for EventSource, which cannot set headers) as the token query parameter
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autholykos/logics/pkg/common"
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/progress"
+	log "github.com/sirupsen/logrus"
+)
+
+// Server serves the API. The endpoints are:
+//
+//	GET    /api/projects                   installed projects (?fetch=true)
+//	GET    /api/remotes                    projects in the shared folder
+//	GET    /api/projects/<name>            status
+//	GET    /api/projects/<name>/history    latest commits (?n=20)
+//	POST   /api/projects/<name>/download   download
+//	POST   /api/projects/<name>/upload     upload ({"message": "...", "owner": "...", "force": false},
+//	                                       the message generated when empty)
+//	GET    /api/projects/<name>/lock       who locked the project (null when nobody)
+//	POST   /api/projects/<name>/lock       lock ({"owner": "..."}, the user when empty)
+//	DELETE /api/projects/<name>/lock       unlock ({"owner": "...", "force": false})
+//	GET    /api/events                     server-sent events
+//
+// Only one operation runs at a time: the others get 409 Conflict. So do the
+// uploads of a project somebody else than the owner locked, unless forced
+type Server struct {
+	store  Store
+	client *logics.Client
+	token  string
+	events *broker
+
+	mu sync.Mutex
+	// current is the project being worked on, empty when idle
+	current string
+}
+
+// Store loads the configuration of the projects
+type Store interface {
+	Load() (*config.Conf, error)
+}
+
+// New creates a Server running the operations through client on the
+// projects configured in store
+func New(store Store, client *logics.Client, token string) *Server {
+	return &Server{
+		store:  store,
+		client: client,
+		token:  token,
+		events: newBroker(),
+	}
+}
+
+// NewToken generates a random token
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Message publishes a message for the user. It is meant to be hooked to the
+// output of the git commands
+func (s *Server) Message(msg string) {
+	if strings.TrimSpace(msg) == "" {
+		return
+	}
+	s.publish("message", msg)
+}
+
+// Progress publishes the progress of large file transfers. It is meant to be
+// hooked to git.Exec
+func (s *Server) Progress() (progress.Renderer, time.Duration) {
+	return progressRenderer{s}, progressInterval
+}
+
+func (s *Server) publish(typ string, data interface{}) {
+	s.mu.Lock()
+	e := Event{Type: typ, Project: s.current, Data: data}
+	s.mu.Unlock()
+	s.events.publish(e)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or wrong token"))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "api" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "events":
+		s.serveEvents(w, r)
+	case len(parts) == 2 && parts[1] == "projects":
+		s.onlyGET(w, r, s.list)
+	case len(parts) == 2 && parts[1] == "remotes":
+		s.onlyGET(w, r, s.remotes)
+	case len(parts) == 3 && parts[1] == "projects":
+		s.onlyGET(w, r, func(w http.ResponseWriter, r *http.Request) { s.status(w, parts[2]) })
+	case len(parts) == 4 && parts[1] == "projects":
+		s.project(w, r, parts[2], parts[3])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) onlyGET(w http.ResponseWriter, r *http.Request, h http.HandlerFunc) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+	h(w, r)
+}
+
+func (s *Server) project(w http.ResponseWriter, r *http.Request, name, action string) {
+	switch {
+	case action == "history" && r.Method == http.MethodGet:
+		s.history(w, r, name)
+	case action == "download" && r.Method == http.MethodPost:
+		s.run(w, name, func(repo config.Repo) (interface{}, error) {
+			return s.client.Download(repo)
+		})
+	case action == "upload" && r.Method == http.MethodPost:
+		var req struct {
+			Message string `json:"message"`
+			Owner   string `json:"owner"`
+			Force   bool   `json:"force"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		s.run(w, name, func(repo config.Repo) (interface{}, error) {
+			if err := s.client.CheckLock(repo, req.Owner); errors.Is(err, logics.ErrLocked) && !req.Force {
+				return nil, err
+			}
+			return s.client.Upload(repo, req.Message)
+		})
+	case action == "lock":
+		s.lock(w, r, name)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	cfg, err := s.store.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fetch, _ := strconv.ParseBool(r.URL.Query().Get("fetch"))
+	projects := make([]*logics.ProjectInfo, 0, len(cfg.Repos))
+	for _, repo := range cfg.Repos {
+		projects = append(projects, s.client.Describe(cfg, repo, fetch))
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) remotes(w http.ResponseWriter, r *http.Request) {
+	cfg, err := s.store.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	remotes, err := s.client.Remotes(cfg)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, remotes)
+}
+
+func (s *Server) status(w http.ResponseWriter, name string) {
+	repo, ok := s.find(w, name)
+	if !ok {
+		return
+	}
+
+	res, err := s.client.Status(repo)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) history(w http.ResponseWriter, r *http.Request, name string) {
+	repo, ok := s.find(w, name)
+	if !ok {
+		return
+	}
+
+	n := 20
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid number of commits %s", v))
+			return
+		}
+	}
+
+	commits, err := s.client.History(repo.Location, n)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.client.Annotate(repo.Location, commits)
+	writeJSON(w, http.StatusOK, commits)
+}
+
+func (s *Server) lock(w http.ResponseWriter, r *http.Request, name string) {
+	repo, ok := s.find(w, name)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Owner string `json:"owner"`
+		Force bool   `json:"force"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var (
+		res interface{}
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		res, err = s.client.LockOf(repo)
+	case http.MethodPost:
+		res, err = s.client.Lock(repo, req.Owner)
+	case http.MethodDelete:
+		err = s.client.Unlock(repo, req.Owner, req.Force)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	switch {
+	case errors.Is(err, logics.ErrLocked):
+		writeError(w, http.StatusConflict, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// decode decodes the JSON body of r into v, answering 400 if it is invalid.
+// An empty body leaves v as it is
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+// run runs an operation changing a project, unless another one is running.
+// Besides the response, the outcome gets published as a done or error event
+func (s *Server) run(w http.ResponseWriter, name string, op func(config.Repo) (interface{}, error)) {
+	repo, ok := s.find(w, name)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if busy := s.current; busy != "" {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, fmt.Errorf("busy with project %s", busy))
+		return
+	}
+	s.current = name
+	s.mu.Unlock()
+
+	res, err := op(repo)
+	if err != nil {
+		s.publish("error", err.Error())
+	} else {
+		s.publish("done", res)
+	}
+
+	s.mu.Lock()
+	s.current = ""
+	s.mu.Unlock()
+
+	switch {
+	case errors.Is(err, logics.ErrNoChanges), errors.Is(err, logics.ErrNothingSelected), errors.Is(err, logics.ErrLocked):
+		writeError(w, http.StatusConflict, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// find returns the configured project called name, answering 404 if there is
+// none
+func (s *Server) find(w http.ResponseWriter, name string) (config.Repo, bool) {
+	cfg, err := s.store.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return config.Repo{}, false
+	}
+
+	i, err := cfg.FindRepo(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return config.Repo{}, false
+	}
+	return cfg.Repos[i], true
+}
+
+// serveEvents streams the events as server-sent events until the front-end
+// goes away
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.WithError(err).Warnln("could not encode event")
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// apiError is the body of every error response
+type apiError struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	res := apiError{Error: err.Error()}
+	var execErr *common.ExecErr
+	if errors.As(err, &execErr) && execErr.Reason != common.UnknownReason {
+		res.Reason = execErr.Reason.String()
+	}
+	writeJSON(w, code, res)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Warnln("could not write the response")
+	}
+}
@@ -0,0 +1,223 @@
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/autholykos/logics/pkg/config"
+	"github.com/autholykos/logics/pkg/git"
+	"github.com/autholykos/logics/pkg/logics"
+	"github.com/autholykos/logics/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+const token = "secret"
+
+// newServer serves a configuration with the project "song" on a git.Fake
+func newServer(t *testing.T) (*httptest.Server, *git.Fake, func()) {
+	dir, err := ioutil.TempDir("", "logics-server")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the shared repository holds the lock, the project its .gitignore
+	shared, song := path.Join(dir, "shared"), path.Join(dir, "song")
+	for _, d := range []string{path.Join(shared, "song.git"), song} {
+		if !assert.NoError(t, os.MkdirAll(d, 0755)) {
+			t.FailNow()
+		}
+	}
+
+	store := config.NewStore(path.Join(dir, ".logics.yml"))
+	conf := &config.Conf{
+		SharedFolder: shared,
+		Repos:        []config.Repo{{Name: "song", Location: song, Remote: "logics://shared/song.git"}},
+	}
+	if !assert.NoError(t, store.Save(conf)) {
+		t.FailNow()
+	}
+
+	fake := git.NewFake()
+	client := logics.New(fake, shared)
+	srv := server.New(store, client, token)
+	client.OnMessage = srv.Message
+
+	ts := httptest.NewServer(srv)
+	return ts, fake, func() {
+		ts.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return res
+}
+
+func TestUnauthorized(t *testing.T) {
+	ts, _, cleanup := newServer(t)
+	defer cleanup()
+
+	res, err := http.Get(ts.URL + "/api/projects")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	}
+
+	res, err = http.Get(ts.URL + "/api/projects?token=" + token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ts, fake, cleanup := newServer(t)
+	defer cleanup()
+	fake.Outputs["log -n1 --format=%H%x1f%an%x1f%aI%x1f%s"] = "abc\x1fPippo\x1f2020-02-20T10:00:00+01:00\x1fadded vocals\n"
+
+	res := request(t, http.MethodGet, ts.URL+"/api/projects/song/history?n=1", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var commits []logics.Commit
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&commits))
+	assert.Equal(t, []logics.Commit{{Hash: "abc", Author: "Pippo", Date: "2020-02-20T10:00:00+01:00", Subject: "added vocals"}}, commits)
+	assert.Equal(t, "song", path.Base(fake.Calls[0].Repo))
+}
+
+func TestUnknownProject(t *testing.T) {
+	ts, _, cleanup := newServer(t)
+	defer cleanup()
+
+	res := request(t, http.MethodPost, ts.URL+"/api/projects/other/download", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestUploadEvents(t *testing.T) {
+	ts, fake, cleanup := newServer(t)
+	defer cleanup()
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+
+	// subscribing first, so that no event gets lost
+	events, err := http.Get(ts.URL + "/api/events?token=" + token)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer events.Body.Close()
+
+	res := request(t, http.MethodPost, ts.URL+"/api/projects/song/upload", `{"message": "new vocals"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, fake.Commands(), "commit -m new vocals")
+
+	types := make([]string, 0)
+	scanner := bufio.NewScanner(events.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var e server.Event
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+		assert.Equal(t, "song", e.Project)
+		types = append(types, e.Type)
+		if e.Type == "done" {
+			break
+		}
+	}
+	// the .gitignore update, then the changes
+	assert.Equal(t, []string{"message", "message", "message", "done"}, types)
+}
+
+func TestUploadWithoutMessage(t *testing.T) {
+	ts, fake, cleanup := newServer(t)
+	defer cleanup()
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+
+	res := request(t, http.MethodPost, ts.URL+"/api/projects/song/upload", `{}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, fake.Commands(), "commit -m Added recording Vox#01.wav")
+}
+
+func TestUploadEmptyBody(t *testing.T) {
+	ts, fake, cleanup := newServer(t)
+	defer cleanup()
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+
+	res := request(t, http.MethodPost, ts.URL+"/api/projects/song/upload", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, fake.Commands(), "commit -m Added recording Vox#01.wav")
+
+	res = request(t, http.MethodPost, ts.URL+"/api/projects/song/upload", "{")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestLock(t *testing.T) {
+	ts, _, cleanup := newServer(t)
+	defer cleanup()
+	lock := ts.URL + "/api/projects/song/lock"
+
+	res := request(t, http.MethodGet, lock, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var held *logics.ProjectLock
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&held))
+	assert.Nil(t, held)
+
+	res = request(t, http.MethodPost, lock, `{"owner": "alice"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&held))
+	assert.Equal(t, "alice", held.Owner)
+
+	// alice may lock again, bob may not
+	res = request(t, http.MethodPost, lock, `{"owner": "alice"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res = request(t, http.MethodPost, lock, `{"owner": "bob"}`)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	res = request(t, http.MethodDelete, lock, `{"owner": "bob"}`)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	res = request(t, http.MethodGet, ts.URL+"/api/projects/song", "")
+	var status logics.ProjectStatus
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&status))
+	if assert.NotNil(t, status.Lock) {
+		assert.Equal(t, "alice", status.Lock.Owner)
+	}
+
+	res = request(t, http.MethodDelete, lock, `{"owner": "bob", "force": true}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res = request(t, http.MethodPost, lock, `{"owner": "bob"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestUploadLocked(t *testing.T) {
+	ts, fake, cleanup := newServer(t)
+	defer cleanup()
+	fake.Outputs["status --porcelain -z"] = "?? Audio Files/Vox#01.wav\x00"
+
+	res := request(t, http.MethodPost, ts.URL+"/api/projects/song/lock", `{"owner": "alice"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	upload := ts.URL + "/api/projects/song/upload"
+	res = request(t, http.MethodPost, upload, `{"message": "new vocals", "owner": "bob"}`)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	assert.NotContains(t, fake.Commands(), "commit -m new vocals")
+
+	res = request(t, http.MethodPost, upload, `{"message": "new vocals", "owner": "alice"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res = request(t, http.MethodPost, upload, `{"message": "more vocals", "owner": "bob", "force": true}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, fake.Commands(), "commit -m more vocals")
+}